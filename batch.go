@@ -0,0 +1,109 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"reflect"
+
+	libhead "github.com/celestiaorg/go-header"
+
+	clientbuilder "github.com/celestiaorg/celestia-openrpc/builder"
+	"github.com/celestiaorg/celestia-openrpc/types/blob"
+	"github.com/celestiaorg/celestia-openrpc/types/header"
+	"github.com/celestiaorg/celestia-openrpc/types/share"
+)
+
+// ErrBatchUnsupported is returned when sending a batch through a client that
+// spans several nodes, such as a failover client.
+var ErrBatchUnsupported = errors.New("client: batch requests are not supported by this client")
+
+// Batch collects calls to be sent to the node in a single JSON-RPC batch
+// request, saving a round-trip per call. Batches bypass interceptors and
+// retries, but calls the token does not permit fail without being sent.
+//
+//	results, err := c.Batch().
+//		HeaderGetByHeight(1).
+//		HeaderGetByHeight(2).
+//		Do(ctx)
+type Batch struct {
+	client *Client
+	calls  []*clientbuilder.BatchCall
+}
+
+// BatchResult is the outcome of a call of a batch.
+type BatchResult struct {
+	// Method is the name of the method, e.g. "header.GetByHeight".
+	Method string
+	// Value is the result of the call, e.g. an *header.ExtendedHeader.
+	Value interface{}
+	Err   error
+}
+
+// Batch starts a new batch request.
+func (c *Client) Batch() *Batch {
+	return &Batch{client: c}
+}
+
+// HeaderGetByHeight adds a call to Header.GetByHeight, resulting in an
+// *header.ExtendedHeader.
+func (b *Batch) HeaderGetByHeight(height uint64) *Batch {
+	return b.Call("header.GetByHeight", new(*header.ExtendedHeader), height)
+}
+
+// HeaderGetByHash adds a call to Header.GetByHash, resulting in an
+// *header.ExtendedHeader.
+func (b *Batch) HeaderGetByHash(hash libhead.Hash) *Batch {
+	return b.Call("header.GetByHash", new(*header.ExtendedHeader), hash)
+}
+
+// BlobGet adds a call to Blob.Get, resulting in a *blob.Blob.
+func (b *Batch) BlobGet(height uint64, namespace share.Namespace, commitment blob.Commitment) *Batch {
+	return b.Call("blob.Get", new(*blob.Blob), height, namespace, commitment)
+}
+
+// BlobGetAll adds a call to Blob.GetAll, resulting in a []*blob.Blob.
+func (b *Batch) BlobGetAll(height uint64, namespaces []share.Namespace) *Batch {
+	return b.Call("blob.GetAll", new([]*blob.Blob), height, namespaces)
+}
+
+// Call adds a call to any method, given by its name, e.g. "share.GetEDS". The
+// result is decoded into result, which must be a pointer, and reported as the
+// value it points to. If result is nil, the result is discarded.
+func (b *Batch) Call(method string, result interface{}, params ...interface{}) *Batch {
+	b.calls = append(b.calls, &clientbuilder.BatchCall{
+		Method: method,
+		Params: params,
+		Result: result,
+	})
+	return b
+}
+
+// Do sends the batch and returns the results in the order the calls were
+// added. The error reports a failure of the request as a whole, while calls
+// fail individually through their result.
+func (b *Batch) Do(ctx context.Context) ([]BatchResult, error) {
+	if b.client.dialer == nil {
+		return nil, ErrBatchUnsupported
+	}
+
+	send := make([]*clientbuilder.BatchCall, 0, len(b.calls))
+	for _, call := range b.calls {
+		if m, ok := method(call.Method); ok && b.client.perms != nil && !b.client.perms.Allows(m) {
+			call.Err = &PermissionError{Method: call.Method, Required: m.Perm, Granted: b.client.perms}
+			continue
+		}
+		send = append(send, call)
+	}
+	if err := b.client.dialer.SendBatch(ctx, send); err != nil {
+		return nil, err
+	}
+
+	results := make([]BatchResult, len(b.calls))
+	for i, call := range b.calls {
+		results[i] = BatchResult{Method: call.Method, Err: call.Err}
+		if call.Err == nil && call.Result != nil {
+			results[i].Value = reflect.ValueOf(call.Result).Elem().Interface()
+		}
+	}
+	return results, nil
+}
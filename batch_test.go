@@ -0,0 +1,45 @@
+package client_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	client "github.com/celestiaorg/celestia-openrpc"
+	"github.com/celestiaorg/celestia-openrpc/testnode"
+	"github.com/celestiaorg/celestia-openrpc/types/header"
+)
+
+func TestBatch(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	node, err := testnode.New()
+	require.NoError(t, err)
+	defer node.Close()
+	_, err = node.ProduceBlock()
+	require.NoError(t, err)
+	c, err := client.NewClient(ctx, node.URL(), "")
+	require.NoError(t, err)
+	defer c.Close()
+
+	results, err := c.Batch().
+		HeaderGetByHeight(1).
+		// the result is discarded
+		Call("header.GetByHeight", nil, 1).
+		Call("header.GetByHeight", nil, 1_000).
+		Do(ctx)
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+
+	require.NoError(t, results[0].Err)
+	h, ok := results[0].Value.(*header.ExtendedHeader)
+	require.True(t, ok)
+	require.EqualValues(t, 1, h.Height())
+
+	require.Equal(t, client.BatchResult{Method: "header.GetByHeight"}, results[1])
+	require.Nil(t, results[2].Value)
+	require.Error(t, results[2].Err)
+}
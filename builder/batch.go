@@ -0,0 +1,149 @@
+package clientbuilder
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// BatchCall is a call sent as part of a batch request.
+type BatchCall struct {
+	// Method is the name of the method as sent over the wire, e.g.
+	// "header.GetByHeight".
+	Method string
+	// Params are the arguments of the call, without the leading context.
+	Params []interface{}
+	// Result points to the value the result is decoded into.
+	Result interface{}
+	// Err is set if the call failed.
+	Err error
+}
+
+// RPCError is an error returned by the node for a call of a batch.
+type RPCError struct {
+	Code    int
+	Message string
+	Meta    json.RawMessage
+}
+
+// Error matches the errors returned by go-jsonrpc for single calls.
+func (e *RPCError) Error() string {
+	if e.Code >= -32768 && e.Code <= -32000 {
+		return fmt.Sprintf("RPC error (%d): %s", e.Code, e.Message)
+	}
+	return e.Message
+}
+
+type batchRequest struct {
+	Jsonrpc string        `json:"jsonrpc"`
+	ID      int           `json:"id"`
+	Method  string        `json:"method"`
+	Params  []interface{} `json:"params"`
+}
+
+type batchResponse struct {
+	ID     int             `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    int             `json:"code"`
+		Message string          `json:"message"`
+		Meta    json.RawMessage `json:"meta,omitempty"`
+	} `json:"error"`
+}
+
+// SendBatch sends the calls to the node in a single JSON-RPC 2.0 batch request.
+// Batches always go over HTTP, also for websocket addresses, as served by
+// celestia-node on the same port. The outcome of each call is set in its Result
+// or Err, while the returned error reports a failure of the request as a whole.
+func (d *Dialer) SendBatch(ctx context.Context, calls []*BatchCall) error {
+	if len(calls) == 0 {
+		return nil
+	}
+
+	reqs := make([]batchRequest, len(calls))
+	for i, call := range calls {
+		params := call.Params
+		if params == nil {
+			params = []interface{}{}
+		}
+		reqs[i] = batchRequest{Jsonrpc: "2.0", ID: i, Method: call.Method, Params: params}
+	}
+	body, err := json.Marshal(reqs)
+	if err != nil {
+		return fmt.Errorf("encoding batch: %w", err)
+	}
+
+	addr, err := httpAddr(d.addr)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, addr, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header = d.header()
+	req.Header.Set("Content-Type", "application/json")
+
+	httpClient := d.httpClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("sending batch: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("sending batch: %s: %s", resp.Status, bytes.TrimSpace(msg))
+	}
+
+	var resps []batchResponse
+	if err := json.NewDecoder(resp.Body).Decode(&resps); err != nil {
+		return fmt.Errorf("decoding batch response: %w", err)
+	}
+
+	answered := make([]bool, len(calls))
+	for _, r := range resps {
+		if r.ID < 0 || r.ID >= len(calls) || answered[r.ID] {
+			return fmt.Errorf("batch response has unexpected id %d", r.ID)
+		}
+		answered[r.ID] = true
+
+		call := calls[r.ID]
+		switch {
+		case r.Error != nil:
+			call.Err = d.cfg.errors.Decode(&RPCError{Code: r.Error.Code, Message: r.Error.Message, Meta: r.Error.Meta})
+		case call.Result != nil:
+			if err := json.Unmarshal(r.Result, call.Result); err != nil {
+				call.Err = fmt.Errorf("decoding result of %s: %w", call.Method, err)
+			}
+		}
+	}
+	for i, ok := range answered {
+		if !ok {
+			calls[i].Err = errors.New("no response in batch")
+		}
+	}
+	return nil
+}
+
+// httpAddr returns the http(s) equivalent of a websocket address.
+func httpAddr(addr string) (string, error) {
+	u, err := url.Parse(addr)
+	if err != nil {
+		return "", fmt.Errorf("parsing address: %w", err)
+	}
+	switch u.Scheme {
+	case "ws":
+		u.Scheme = "http"
+	case "wss":
+		u.Scheme = "https"
+	}
+	return u.String(), nil
+}
@@ -0,0 +1,83 @@
+package clientbuilder
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/filecoin-project/go-jsonrpc"
+	"github.com/stretchr/testify/require"
+)
+
+type batchHandler struct{}
+
+func (batchHandler) Double(_ context.Context, v int) (int, error) {
+	if v < 0 {
+		return 0, errors.New("negative")
+	}
+	return 2 * v, nil
+}
+
+func TestSendBatch(t *testing.T) {
+	rpc := jsonrpc.NewServer()
+	rpc.Register("test", batchHandler{})
+
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		require.Equal(t, "Bearer token", r.Header.Get(AuthKey))
+		rpc.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+
+	// websocket addresses are sent over http
+	dialer, err := NewDialer(context.Background(), "ws"+strings.TrimPrefix(srv.URL, "http"), "token")
+	require.NoError(t, err)
+
+	results := make([]int, 3)
+	calls := []*BatchCall{
+		{Method: "test.Double", Params: []interface{}{1}, Result: &results[0]},
+		{Method: "test.Double", Params: []interface{}{-1}, Result: &results[1]},
+		{Method: "test.Double", Params: []interface{}{3}, Result: &results[2]},
+	}
+	require.NoError(t, dialer.SendBatch(context.Background(), calls))
+	require.EqualValues(t, 1, requests.Load())
+
+	require.NoError(t, calls[0].Err)
+	require.Equal(t, 2, results[0])
+	require.EqualError(t, calls[1].Err, "negative")
+	require.NoError(t, calls[2].Err)
+	require.Equal(t, 6, results[2])
+}
+
+// TestSendBatch_ReusesConnections ensures that batches share the connections
+// of a single HTTP client, also when the options need a custom transport.
+func TestSendBatch_ReusesConnections(t *testing.T) {
+	rpc := jsonrpc.NewServer()
+	rpc.Register("test", batchHandler{})
+	srv := httptest.NewUnstartedServer(rpc)
+	var conns atomic.Int32
+	srv.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			conns.Add(1)
+		}
+	}
+	srv.Start()
+	defer srv.Close()
+
+	dialer, err := NewDialer(context.Background(), srv.URL, "", WithDialer((&net.Dialer{}).DialContext))
+	require.NoError(t, err)
+	for i := 0; i < 3; i++ {
+		var result int
+		calls := []*BatchCall{{Method: "test.Double", Params: []interface{}{i}, Result: &result}}
+		require.NoError(t, dialer.SendBatch(context.Background(), calls))
+		require.NoError(t, calls[0].Err)
+		require.Equal(t, 2*i, result)
+	}
+	require.EqualValues(t, 1, conns.Load())
+}
@@ -0,0 +1,119 @@
+package clientbuilder
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// Closer closes the connection of a client.
+type Closer func()
+
+// WithToken sets the token sent as a bearer token with every request.
+func WithToken(token string) Option {
+	return func(c *config) {
+		c.token = token
+	}
+}
+
+// Build returns a client of type T connected to the node served at addr. T
+// must be a struct whose exported fields are API modules, such as blob.API,
+// named by their lowercased field name unless overridden by an rpc tag:
+//
+//	type Client struct {
+//		Blob   blob.API
+//		Header header.API `rpc:"header"`
+//	}
+//
+// Structs grouping modules may be embedded to compose clients from subsets of
+// modules. Every method must declare the permission it requires in a perm tag.
+// The closer closes the connection and must be called once the client is no
+// longer used.
+func Build[T any](ctx context.Context, addr string, opts ...Option) (*T, Closer, error) {
+	client := new(T)
+	if err := validate(reflect.TypeOf(client).Elem()); err != nil {
+		return nil, nil, err
+	}
+
+	dialer, err := NewDialer(ctx, addr, "", opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+	closer, err := dialer.Connect(ctx, client)
+	if err != nil {
+		return nil, nil, err
+	}
+	return client, closer, nil
+}
+
+// Connect dials the modules of client, which must be a pointer to a struct of
+// modules, and installs the permission check and middleware. The returned
+// closer also closes the dialer.
+func (d *Dialer) Connect(ctx context.Context, client interface{}) (Closer, error) {
+	closer, err := d.DialAll(ctx, client)
+	if err != nil {
+		d.Close()
+		return nil, err
+	}
+	closeAll := func() {
+		closer()
+		d.Close()
+	}
+
+	if perms := d.Permissions(ctx, nil); perms != nil {
+		err = CheckPermissions(client, perms)
+	}
+	if err == nil {
+		// installed last, so that denied calls go through the middleware too
+		err = d.Wrap(client)
+	}
+	if err == nil {
+		err = d.RunChecks(ctx, client)
+	}
+	if err != nil {
+		closeAll()
+		return nil, err
+	}
+	return closeAll, nil
+}
+
+// validate checks that t is a struct of modules whose methods all declare a
+// known permission, beyond what Methods requires.
+func validate(t reflect.Type) error {
+	if t.Kind() != reflect.Struct {
+		return fmt.Errorf("client must be a struct, got %s", t)
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() && !field.Anonymous {
+			continue
+		}
+		if field.Type.Kind() != reflect.Struct {
+			return fmt.Errorf("field %s of %s is not a module: want a struct, got %s", field.Name, t, field.Type)
+		}
+		if _, tagged := field.Tag.Lookup("rpc"); field.Anonymous && !tagged {
+			if hasFuncs(field.Type) {
+				return fmt.Errorf("embedded module %s needs an rpc tag naming its namespace", field.Name)
+			}
+			if err := validate(field.Type); err != nil {
+				return err
+			}
+			continue
+		}
+
+		for j := 0; j < field.Type.NumField(); j++ {
+			method := field.Type.Field(j)
+			if method.Type.Kind() != reflect.Func {
+				return fmt.Errorf("field %s.%s is not a method: want a func, got %s", field.Name, method.Name, method.Type)
+			}
+			switch perm := method.Tag.Get("perm"); perm {
+			case string(PermRead), string(PermWrite), string(PermAdmin):
+			default:
+				return fmt.Errorf("method %s.%s has unknown permission %q", field.Name, method.Name, perm)
+			}
+		}
+	}
+	// Methods checks the rest, e.g. that namespaces are unique
+	_, err := Methods(reflect.New(t).Interface())
+	return err
+}
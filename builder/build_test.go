@@ -0,0 +1,82 @@
+package clientbuilder
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/filecoin-project/go-jsonrpc"
+	"github.com/stretchr/testify/require"
+)
+
+type getModule struct {
+	Get func(context.Context) (int, error) `perm:"read"`
+}
+
+type testGroup struct {
+	Test getModule
+}
+
+type composedClient struct {
+	testGroup
+	Renamed getModule `rpc:"other"`
+}
+
+func TestBuild(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	rpc := jsonrpc.NewServer()
+	rpc.Register("test", testHandler{})
+	rpc.Register("other", testHandler{})
+	srv := httptest.NewServer(rpc)
+	defer srv.Close()
+
+	client, closer, err := Build[composedClient](ctx, "ws"+strings.TrimPrefix(srv.URL, "http"))
+	require.NoError(t, err)
+	defer closer()
+
+	v, err := client.Test.Get(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, v)
+	v, err = client.Renamed.Get(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, v)
+}
+
+func TestBuild_Validate(t *testing.T) {
+	tests := map[string]func() error{
+		"not a struct": func() error {
+			_, _, err := Build[int](context.Background(), "http://localhost")
+			return err
+		},
+		"field is not a module": func() error {
+			_, _, err := Build[struct{ Test *getModule }](context.Background(), "http://localhost")
+			return err
+		},
+		"missing perm": func() error {
+			_, _, err := Build[struct {
+				Test struct {
+					Get func(context.Context) (int, error)
+				}
+			}](context.Background(), "http://localhost")
+			return err
+		},
+		"duplicate namespace": func() error {
+			_, _, err := Build[struct {
+				Test  getModule
+				Other getModule `rpc:"test"`
+			}](context.Background(), "http://localhost")
+			return err
+		},
+		"embedded module without tag": func() error {
+			_, _, err := Build[struct{ getModule }](context.Background(), "http://localhost")
+			return err
+		},
+	}
+	for name, build := range tests {
+		require.Error(t, build(), name)
+	}
+}
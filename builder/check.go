@@ -0,0 +1,24 @@
+package clientbuilder
+
+import "context"
+
+// StartupCheck inspects a freshly connected client, given as the pointer to
+// its struct, failing its creation by returning an error.
+type StartupCheck func(ctx context.Context, client interface{}) error
+
+// WithStartupCheck runs check once the client is connected.
+func WithStartupCheck(check StartupCheck) Option {
+	return func(c *config) {
+		c.checks = append(c.checks, check)
+	}
+}
+
+// RunChecks runs the configured startup checks against client.
+func (d *Dialer) RunChecks(ctx context.Context, client interface{}) error {
+	for _, check := range d.cfg.checks {
+		if err := check(ctx, client); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -4,8 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
-	"reflect"
-	"strings"
+	"net/url"
 
 	"github.com/filecoin-project/go-jsonrpc"
 )
@@ -29,23 +28,105 @@ func (m *MultiClientCloser) CloseAll() {
 	}
 }
 
-func NewClient(ctx context.Context, addr string, token string, client interface{}) (interface{}, error) {
-	var authHeader http.Header
-	if token != "" {
-		authHeader = http.Header{AuthKey: []string{fmt.Sprintf("Bearer %s", token)}}
+// Dialer opens connections for namespaces served at a single address, all
+// sharing the same options.
+type Dialer struct {
+	addr      string
+	websocket bool
+	cfg       *config
+	rpcOpts   []jsonrpc.Option
+	tokens    *tokenSource
+	// httpClient sends the requests going over HTTP, batches included. It
+	// is resolved once, so that its connections are reused.
+	httpClient *http.Client
+}
+
+// NewDialer resolves the options for addr, which is an http(s), ws(s) or
+// unix:///path/to/socket address. Requests to unix sockets go over HTTP. The
+// token, if not empty, is sent as a bearer token with every request, unless a
+// TokenProvider is configured.
+func NewDialer(ctx context.Context, addr string, token string, opts ...Option) (*Dialer, error) {
+	u, err := url.Parse(addr)
+	if err != nil {
+		return nil, fmt.Errorf("parsing address: %w", err)
 	}
 
-	v := reflect.ValueOf(client).Elem()
-	t := v.Type()
-	for i := 0; i < t.NumField(); i++ {
-		field := t.Field(i)
-		name := strings.ToLower(field.Name)
-		module := v.Field(i).Addr().Interface()
-		_, err := jsonrpc.NewClient(ctx, addr, name, module, authHeader)
+	d := &Dialer{
+		addr:      addr,
+		websocket: u.Scheme == "ws" || u.Scheme == "wss",
+		cfg:       newConfig(token, opts),
+	}
+	if u.Scheme == "unix" {
+		// requests go over HTTP to the socket, whatever the host
+		if u.Path == "" {
+			return nil, fmt.Errorf("missing socket path in address %q", addr)
+		}
+		d.cfg.socket = u.Path
+		d.addr = "http://unix/"
+		u.Scheme = "http"
+	}
+	if d.cfg.tokenProvider != nil {
+		d.tokens, err = newTokenSource(ctx, d.cfg.tokenProvider, d.cfg.tokenRefresh)
 		if err != nil {
 			return nil, err
 		}
 	}
 
+	d.httpClient, err = d.cfg.resolveHTTPClient(d.tokens)
+	if err == nil {
+		d.rpcOpts, err = d.cfg.options(u.Scheme, d.httpClient)
+	}
+	if err != nil {
+		d.Close()
+		return nil, err
+	}
+	return d, nil
+}
+
+// Dial fills in the function fields of module, which must be a pointer to a
+// struct, with calls to the given namespace.
+func (d *Dialer) Dial(ctx context.Context, namespace string, module interface{}) (jsonrpc.ClientCloser, error) {
+	if d.tokens != nil && d.websocket {
+		return d.dialRotating(ctx, namespace, module)
+	}
+	return jsonrpc.NewMergeClient(ctx, d.addr, namespace, []interface{}{module}, d.header(), d.rpcOpts...)
+}
+
+// Close stops refreshing the token. Connections are closed by their closers.
+func (d *Dialer) Close() {
+	if d.tokens != nil {
+		d.tokens.close()
+	}
+}
+
+// token returns the token currently used to authenticate.
+func (d *Dialer) token() string {
+	if d.tokens != nil {
+		return d.tokens.current()
+	}
+	return d.cfg.token
+}
+
+// header returns the headers to connect with.
+func (d *Dialer) header() http.Header {
+	header := d.cfg.header.Clone()
+	if token := d.token(); token != "" {
+		header.Set(AuthKey, bearer(token))
+	}
+	return header
+}
+
+// NewClient fills in the modules of client, which must be a pointer to a
+// struct, and returns it.
+//
+// Deprecated: the connection can never be closed. Use Build instead.
+func NewClient(ctx context.Context, addr string, token string, client interface{}, opts ...Option) (interface{}, error) {
+	dialer, err := NewDialer(ctx, addr, token, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := dialer.Connect(ctx, client); err != nil {
+		return nil, err
+	}
 	return client, nil
 }
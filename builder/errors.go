@@ -0,0 +1,156 @@
+package clientbuilder
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+
+	"github.com/filecoin-project/go-jsonrpc"
+)
+
+// KnownError is an error of the node that the client decodes into a Go error.
+type KnownError struct {
+	// Code is the JSON-RPC error code the error is sent with by servers that
+	// register it. Zero leaves the error unregistered, which it must be if
+	// its type is shared with other errors, as for errors.New.
+	Code jsonrpc.ErrorCode
+	// Err is the error that decoded errors match in errors.Is.
+	Err error
+	// Type is an error of the type the error is sent as, if not the type of
+	// Err, e.g. a nil pointer to a struct type.
+	Type error
+	// Messages are substrings of the error's message, for nodes that send it
+	// without its code.
+	Messages []string
+	// Parse, if set, returns the error decoded from a message containing one
+	// of Messages. It defaults to a *RemoteError wrapping Err.
+	Parse func(msg string) error
+}
+
+func (k KnownError) typ() reflect.Type {
+	if k.Type != nil {
+		return reflect.TypeOf(k.Type)
+	}
+	return reflect.TypeOf(k.Err)
+}
+
+// RemoteError is a known error of the node, recognized by its message.
+type RemoteError struct {
+	// Err is the error it is known as.
+	Err     error
+	Message string
+}
+
+func (e *RemoteError) Error() string {
+	return e.Message
+}
+
+func (e *RemoteError) Unwrap() error {
+	return e.Err
+}
+
+// ErrorRegistry maps the errors returned by the node to Go errors. Errors sent
+// with a registered code are decoded by go-jsonrpc into their Go type, others
+// are matched by message.
+type ErrorRegistry struct {
+	known []KnownError
+}
+
+// NewErrorRegistry returns a registry of the known errors.
+func NewErrorRegistry(known ...KnownError) *ErrorRegistry {
+	return &ErrorRegistry{known: known}
+}
+
+// Register adds known errors to the registry.
+func (r *ErrorRegistry) Register(known ...KnownError) {
+	r.known = append(r.known, known...)
+}
+
+// RPCErrors returns the registered codes, for go-jsonrpc clients and servers.
+func (r *ErrorRegistry) RPCErrors() jsonrpc.Errors {
+	errs := jsonrpc.NewErrors()
+	for _, k := range r.known {
+		if k.Code != 0 {
+			errs.Register(k.Code, reflect.New(k.typ()).Interface())
+		}
+	}
+	return errs
+}
+
+// Decode returns the known error that err is, or err if it is none. A nil
+// registry decodes nothing.
+func (r *ErrorRegistry) Decode(err error) error {
+	if r == nil || err == nil {
+		return err
+	}
+	for _, k := range r.known {
+		if errors.Is(err, k.Err) {
+			return err
+		}
+	}
+
+	var rpcErr *RPCError
+	if errors.As(err, &rpcErr) {
+		for _, k := range r.known {
+			if k.Code != 0 && k.Code == jsonrpc.ErrorCode(rpcErr.Code) {
+				return k.decode(rpcErr.Meta)
+			}
+		}
+	}
+
+	msg := err.Error()
+	for _, k := range r.known {
+		for _, m := range k.Messages {
+			if !strings.Contains(msg, m) {
+				continue
+			}
+			if k.Parse != nil {
+				return k.Parse(msg)
+			}
+			return &RemoteError{Err: k.Err, Message: msg}
+		}
+	}
+	return err
+}
+
+// decode returns the error for its code, as go-jsonrpc does for single calls.
+func (k KnownError) decode(meta json.RawMessage) error {
+	t := k.typ()
+	if t.Kind() != reflect.Ptr {
+		// sentinels of struct types carry nothing
+		return reflect.Zero(t).Interface().(error)
+	}
+	v := reflect.New(t.Elem())
+	if u, ok := v.Interface().(json.Unmarshaler); ok && len(meta) > 0 {
+		_ = u.UnmarshalJSON(meta)
+	}
+	return v.Interface().(error)
+}
+
+// WithErrors decodes the errors returned by the node into the known errors of
+// the registry.
+func WithErrors(r *ErrorRegistry) Option {
+	return func(c *config) {
+		c.errors = r
+	}
+}
+
+// decodeErrors wraps a method so that the errors it returns are decoded by r.
+func decodeErrors(m Method, r *ErrorRegistry, fn interface{}) reflect.Value {
+	next := reflect.ValueOf(fn)
+	return reflect.MakeFunc(m.Type, func(args []reflect.Value) []reflect.Value {
+		var results []reflect.Value
+		if m.Type.IsVariadic() {
+			results = next.CallSlice(args)
+		} else {
+			results = next.Call(args)
+		}
+		if err := m.Err(results); err != nil {
+			if decoded := r.Decode(err); decoded != err {
+				return m.ErrorResults(decoded)
+			}
+		}
+		return results
+	})
+}
@@ -0,0 +1,98 @@
+package clientbuilder
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var errNotFound = errors.New("test: not found")
+
+// heightError is a typed error carrying its height across the wire.
+type heightError struct {
+	Height uint64
+}
+
+func (e *heightError) Error() string {
+	return "test: bad height " + strconv.FormatUint(e.Height, 10)
+}
+
+func (e *heightError) MarshalJSON() ([]byte, error) {
+	type wire heightError
+	return json.Marshal((*wire)(e))
+}
+
+func (e *heightError) UnmarshalJSON(data []byte) error {
+	type wire heightError
+	return json.Unmarshal(data, (*wire)(e))
+}
+
+func testRegistry() *ErrorRegistry {
+	return NewErrorRegistry(
+		KnownError{Err: errNotFound, Messages: []string{"not found"}},
+		KnownError{Code: 3, Err: &heightError{}, Messages: []string{"bad height"}, Parse: func(string) error {
+			return &heightError{Height: 7}
+		}},
+	)
+}
+
+func TestErrorRegistry_Decode(t *testing.T) {
+	r := testRegistry()
+
+	err := r.Decode(errors.New("getting blob: test: not found"))
+	require.ErrorIs(t, err, errNotFound)
+	require.EqualError(t, err, "getting blob: test: not found")
+
+	var height *heightError
+	err = r.Decode(&RPCError{Code: 3, Message: "test: bad height 5", Meta: json.RawMessage(`{"Height":5}`)})
+	require.ErrorAs(t, err, &height)
+	require.EqualValues(t, 5, height.Height)
+	err = r.Decode(errors.New("test: bad height"))
+	require.ErrorAs(t, err, &height)
+	require.EqualValues(t, 7, height.Height)
+
+	other := errors.New("test: unknown")
+	require.Equal(t, other, r.Decode(other))
+	require.Equal(t, other, (*ErrorRegistry)(nil).Decode(other))
+}
+
+func TestErrorRegistry_Wrap(t *testing.T) {
+	d := &Dialer{cfg: newConfig("", []Option{WithErrors(testRegistry())})}
+	client := &testClient{Test: testModule{
+		Get: func(context.Context) (int, error) {
+			return 0, errors.New("test: not found")
+		},
+		Set: func(context.Context, int) error {
+			return nil
+		},
+	}}
+	require.NoError(t, d.Wrap(client))
+
+	_, err := client.Test.Get(context.Background())
+	require.ErrorIs(t, err, errNotFound)
+	require.NoError(t, client.Test.Set(context.Background(), 1))
+}
+
+// TestErrorRegistry_WrapVariadic ensures that the variadic arguments of calls
+// are passed on as they were given.
+func TestErrorRegistry_WrapVariadic(t *testing.T) {
+	d := &Dialer{cfg: newConfig("", []Option{WithErrors(testRegistry())})}
+	client := &variadicClient{}
+	client.Test.Sum = func(_ context.Context, xs ...int) (int, error) {
+		if len(xs) == 0 {
+			return 0, errors.New("test: not found")
+		}
+		return len(xs), nil
+	}
+	require.NoError(t, d.Wrap(client))
+
+	n, err := client.Test.Sum(context.Background(), 1, 2, 3)
+	require.NoError(t, err)
+	require.Equal(t, 3, n)
+	_, err = client.Test.Sum(context.Background())
+	require.ErrorIs(t, err, errNotFound)
+}
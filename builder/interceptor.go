@@ -0,0 +1,127 @@
+package clientbuilder
+
+import (
+	"context"
+	"reflect"
+	"time"
+)
+
+// Call is a call to a method, as seen by interceptors.
+type Call struct {
+	Method Method
+	// Args are the arguments of the call, without the leading context.
+	Args []interface{}
+
+	// Result, Err and Duration are set once the call returns. Result is the
+	// value returned next to the error, if any, e.g. the channel of a
+	// subscription.
+	Result   interface{}
+	Err      error
+	Duration time.Duration
+}
+
+// Invoker performs a call, filling in its outcome.
+type Invoker func(ctx context.Context, call *Call) error
+
+// Interceptor runs around calls to the node. It calls next to perform the
+// call, after which the outcome is available in call. It may replace ctx, the
+// arguments and the result, as long as the types stay the same, and change the
+// returned error. It may also answer a call without calling next by setting
+// its result, as a cache does.
+type Interceptor func(ctx context.Context, call *Call, next Invoker) error
+
+// WithInterceptors runs calls through the interceptors, the first being the
+// outermost.
+func WithInterceptors(interceptors ...Interceptor) Option {
+	return func(c *config) {
+		c.interceptors = append(c.interceptors, interceptors...)
+	}
+}
+
+// Wrap installs the configured middleware around the methods of client, which
+// must be a pointer to a struct of modules that have been dialed. Errors are
+// decoded innermost, so that retries and interceptors see known errors.
+// Interceptors run outside of retries, so they see each call once.
+func (d *Dialer) Wrap(client interface{}) error {
+	methods, err := Methods(client)
+	if err != nil {
+		return err
+	}
+	for _, m := range methods {
+		fn := m.Value(client)
+		if d.cfg.errors != nil {
+			fn.Set(decodeErrors(m, d.cfg.errors, fn.Interface()))
+		}
+		if policy, ok := d.cfg.retry.policy(m); ok && policy.MaxAttempts > 1 {
+			fn.Set(retry(m, policy, fn.Interface()))
+		}
+		if len(d.cfg.interceptors) > 0 {
+			fn.Set(intercept(m, d.cfg.interceptors, fn.Interface()))
+		}
+	}
+	return nil
+}
+
+// intercept wraps a method so that calls go through the interceptors.
+func intercept(m Method, interceptors []Interceptor, fn interface{}) reflect.Value {
+	next := reflect.ValueOf(fn)
+	takesCtx := m.Type.NumIn() > 0 && m.Type.In(0) == contextType
+
+	invoke := func(ctx context.Context, call *Call) error {
+		args := make([]reflect.Value, 0, m.Type.NumIn())
+		if takesCtx {
+			args = append(args, reflect.ValueOf(&ctx).Elem())
+		}
+		for _, arg := range call.Args {
+			args = append(args, valueOf(arg, m.Type.In(len(args))))
+		}
+
+		start := time.Now()
+		var results []reflect.Value
+		if m.Type.IsVariadic() {
+			results = next.CallSlice(args)
+		} else {
+			results = next.Call(args)
+		}
+		call.Duration = time.Since(start)
+		call.Err = m.Err(results)
+		if len(results) > 1 {
+			call.Result = results[0].Interface()
+		}
+		return call.Err
+	}
+
+	chain := Invoker(invoke)
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, inner := interceptors[i], chain
+		chain = func(ctx context.Context, call *Call) error {
+			return interceptor(ctx, call, inner)
+		}
+	}
+
+	return reflect.MakeFunc(m.Type, func(args []reflect.Value) []reflect.Value {
+		ctx := m.Context(args)
+		call := &Call{Method: m}
+		for _, arg := range args {
+			call.Args = append(call.Args, arg.Interface())
+		}
+		if takesCtx {
+			call.Args = call.Args[1:]
+		}
+
+		err := chain(ctx, call)
+		results := m.ErrorResults(err)
+		if len(results) > 1 {
+			results[0] = valueOf(call.Result, m.Type.Out(0))
+		}
+		return results
+	})
+}
+
+// valueOf converts v to a value of type t, mapping nil to the zero value.
+func valueOf(v interface{}, t reflect.Type) reflect.Value {
+	if v == nil {
+		return reflect.Zero(t)
+	}
+	return reflect.ValueOf(v).Convert(t)
+}
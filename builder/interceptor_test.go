@@ -0,0 +1,64 @@
+package clientbuilder
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInterceptors(t *testing.T) {
+	type ctxKey struct{}
+	var order []string
+	var calls []Call
+	record := func(ctx context.Context, call *Call, next Invoker) error {
+		order = append(order, "record")
+		err := next(context.WithValue(ctx, ctxKey{}, "traced"), call)
+		calls = append(calls, *call)
+		return err
+	}
+	double := func(ctx context.Context, call *Call, next Invoker) error {
+		order = append(order, "double")
+		if call.Method.Name == "Set" {
+			call.Args[0] = call.Args[0].(int) * 2
+		}
+		err := next(ctx, call)
+		if v, ok := call.Result.(int); ok {
+			call.Result = v * 2
+		}
+		return err
+	}
+	d := &Dialer{cfg: newConfig("", []Option{WithInterceptors(record, double)})}
+
+	var set int
+	client := &testClient{Test: testModule{
+		Get: func(ctx context.Context) (int, error) {
+			require.Equal(t, "traced", ctx.Value(ctxKey{}))
+			return 21, nil
+		},
+		Set: func(_ context.Context, v int) error {
+			set = v
+			return errors.New("failed")
+		},
+	}}
+	require.NoError(t, d.Wrap(client))
+
+	v, err := client.Test.Get(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 42, v)
+	require.Equal(t, []string{"record", "double"}, order)
+
+	err = client.Test.Set(context.Background(), 1)
+	require.EqualError(t, err, "failed")
+	require.Equal(t, 2, set)
+
+	require.Len(t, calls, 2)
+	require.Equal(t, "test.Get", calls[0].Method.String())
+	require.Equal(t, 42, calls[0].Result)
+	require.Empty(t, calls[0].Args)
+	require.Equal(t, "test.Set", calls[1].Method.String())
+	require.Equal(t, []interface{}{2}, calls[1].Args)
+	require.EqualError(t, calls[1].Err, "failed")
+	require.Positive(t, calls[1].Duration)
+}
@@ -0,0 +1,171 @@
+package clientbuilder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/filecoin-project/go-jsonrpc/auth"
+)
+
+// Permissions required by the methods of the API, as declared in their perm
+// tags.
+const (
+	PermRead  auth.Permission = "read"
+	PermWrite auth.Permission = "write"
+	PermAdmin auth.Permission = "admin"
+)
+
+var (
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
+	contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+)
+
+// Method describes an RPC method, which is declared as a function field of a
+// module struct.
+type Method struct {
+	// Namespace is the name of the module the method belongs to, e.g. "blob".
+	Namespace string
+	// Name is the name of the method within its module, e.g. "Submit".
+	Name string
+	// Perm is the permission required to call the method.
+	Perm auth.Permission
+	// Type is the function type of the method.
+	Type reflect.Type
+
+	// index locates the function field within the client struct.
+	index []int
+}
+
+// String returns the name of the method as sent over the wire, e.g.
+// "blob.Submit".
+func (m Method) String() string {
+	return m.Namespace + "." + m.Name
+}
+
+// Value returns the function field of the method within client, which must be
+// a pointer to the struct the method was listed from.
+func (m Method) Value(client interface{}) reflect.Value {
+	return reflect.ValueOf(client).Elem().FieldByIndex(m.index)
+}
+
+// Err returns the error among the results of a call to the method.
+func (m Method) Err(results []reflect.Value) error {
+	if len(results) == 0 {
+		return nil
+	}
+	last := results[len(results)-1]
+	if last.Type() != errorType || last.IsNil() {
+		return nil
+	}
+	return last.Interface().(error)
+}
+
+// ErrorResults returns the results of a failed call to the method: zero values
+// followed by err.
+func (m Method) ErrorResults(err error) []reflect.Value {
+	results := make([]reflect.Value, m.Type.NumOut())
+	for i := range results {
+		results[i] = reflect.Zero(m.Type.Out(i))
+	}
+	if len(results) > 0 {
+		results[len(results)-1] = reflect.ValueOf(&err).Elem()
+	}
+	return results
+}
+
+// Context returns the context passed to a call to the method, or
+// context.Background if it takes none.
+func (m Method) Context(args []reflect.Value) context.Context {
+	if len(args) > 0 && m.Type.In(0) == contextType && !args[0].IsNil() {
+		return args[0].Interface().(context.Context)
+	}
+	return context.Background()
+}
+
+// Methods lists the methods of every module of client, which must be a pointer
+// to a struct whose exported fields are module structs. Modules are named by
+// their rpc tag or else their lowercased field name, as done by NewClient.
+// Embedded structs without methods of their own group further modules.
+func Methods(client interface{}) ([]Method, error) {
+	v := reflect.ValueOf(client)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("client must be a pointer to a struct, got %T", client)
+	}
+
+	var methods []Method
+	if err := collectMethods(v.Elem().Type(), nil, map[string]string{}, &methods); err != nil {
+		return nil, err
+	}
+	if len(methods) == 0 {
+		return nil, errors.New("client declares no methods")
+	}
+	return methods, nil
+}
+
+// collectMethods appends the methods of the modules declared by t, found at
+// index within the client. namespaces maps the namespaces seen so far to their
+// field.
+func collectMethods(t reflect.Type, index []int, namespaces map[string]string, methods *[]Method) error {
+	for i := 0; i < t.NumField(); i++ {
+		module := t.Field(i)
+		if !(module.IsExported() || module.Anonymous) || module.Type.Kind() != reflect.Struct {
+			continue
+		}
+		moduleIndex := append(append([]int{}, index...), i)
+
+		namespace, tagged := module.Tag.Lookup("rpc")
+		if module.Anonymous && !tagged {
+			if hasFuncs(module.Type) {
+				return fmt.Errorf("embedded module %s needs an rpc tag naming its namespace", module.Name)
+			}
+			if err := collectMethods(module.Type, moduleIndex, namespaces, methods); err != nil {
+				return err
+			}
+			continue
+		}
+		if !tagged {
+			namespace = strings.ToLower(module.Name)
+		}
+		if namespace == "" {
+			return fmt.Errorf("module %s has an empty rpc tag", module.Name)
+		}
+		if other, ok := namespaces[namespace]; ok {
+			return fmt.Errorf("modules %s and %s share the namespace %q", other, module.Name, namespace)
+		}
+		namespaces[namespace] = module.Name
+
+		for j := 0; j < module.Type.NumField(); j++ {
+			field := module.Type.Field(j)
+			if field.Type.Kind() != reflect.Func {
+				continue
+			}
+			if !returnsError(field.Type) {
+				return fmt.Errorf("method %s.%s must return an error", module.Name, field.Name)
+			}
+			*methods = append(*methods, Method{
+				Namespace: namespace,
+				Name:      field.Name,
+				Perm:      auth.Permission(field.Tag.Get("perm")),
+				Type:      field.Type,
+				index:     append(append([]int{}, moduleIndex...), j),
+			})
+		}
+	}
+	return nil
+}
+
+func hasFuncs(t reflect.Type) bool {
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).Type.Kind() == reflect.Func {
+			return true
+		}
+	}
+	return false
+}
+
+func returnsError(t reflect.Type) bool {
+	return t.NumOut() > 0 && t.Out(t.NumOut()-1) == errorType
+}
@@ -0,0 +1,197 @@
+package clientbuilder
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/filecoin-project/go-jsonrpc"
+)
+
+// Option configures the connections opened to the node for every namespace.
+type Option func(*config)
+
+// config collects everything needed to dial a namespace. It is resolved
+// against the node address only when dialing, as some options depend on the
+// transport in use.
+type config struct {
+	token      string
+	header     http.Header
+	httpClient *http.Client
+	tlsConfig  *tls.Config
+	timeout    time.Duration
+	dial       DialFunc
+	// socket is the path of the unix socket of unix:// addresses.
+	socket string
+
+	rpcOptions []jsonrpc.Option
+
+	perms       Permissions
+	noPermCheck bool
+
+	tokenProvider TokenProvider
+	tokenRefresh  time.Duration
+
+	retry        retryPolicies
+	interceptors []Interceptor
+	errors       *ErrorRegistry
+
+	lazy   bool
+	checks []StartupCheck
+}
+
+func newConfig(token string, opts []Option) *config {
+	cfg := &config{token: token, header: http.Header{}}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+// WithHTTPClient sets the HTTP client used for http(s) addresses.
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *config) {
+		c.httpClient = client
+	}
+}
+
+// WithTLSConfig sets the TLS configuration, e.g. client certificates for mTLS,
+// used for https addresses. If a custom HTTP client is set as well, its
+// transport must be an *http.Transport.
+func WithTLSConfig(tlsConfig *tls.Config) Option {
+	return func(c *config) {
+		c.tlsConfig = tlsConfig
+	}
+}
+
+// DialFunc opens connections to the node, as net.Dialer.DialContext does.
+type DialFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// WithDialer opens the connections to the node with dial. It is only
+// supported for http(s) and unix addresses, as go-jsonrpc dials websockets
+// itself.
+func WithDialer(dial DialFunc) Option {
+	return func(c *config) {
+		c.dial = dial
+	}
+}
+
+// WithHeader adds a header to every request sent to the node.
+func WithHeader(key, value string) Option {
+	return func(c *config) {
+		c.header.Add(key, value)
+	}
+}
+
+// WithTimeout sets the per-request timeout for http(s) addresses and the read
+// timeout of websocket connections.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *config) {
+		c.timeout = timeout
+		c.rpcOptions = append(c.rpcOptions, jsonrpc.WithTimeout(timeout))
+	}
+}
+
+// WithPingInterval sets the interval between websocket pings. It must be less
+// than half of the timeout.
+func WithPingInterval(interval time.Duration) Option {
+	return func(c *config) {
+		c.rpcOptions = append(c.rpcOptions, jsonrpc.WithPingInterval(interval))
+	}
+}
+
+// WithReconnect sets the backoff between attempts to re-establish a dropped
+// websocket connection.
+func WithReconnect(minDelay, maxDelay time.Duration) Option {
+	return func(c *config) {
+		c.rpcOptions = append(c.rpcOptions, jsonrpc.WithReconnectBackoff(minDelay, maxDelay))
+	}
+}
+
+// WithNoReconnect disables re-establishing dropped websocket connections.
+func WithNoReconnect() Option {
+	return func(c *config) {
+		c.rpcOptions = append(c.rpcOptions, jsonrpc.WithNoReconnect())
+	}
+}
+
+// options resolves the config into go-jsonrpc options for the given address
+// scheme. httpClient, as resolved by resolveHTTPClient, is used for http(s)
+// addresses unless it is nil.
+func (c *config) options(scheme string, httpClient *http.Client) ([]jsonrpc.Option, error) {
+	opts := append([]jsonrpc.Option{}, c.rpcOptions...)
+	if c.errors != nil {
+		opts = append(opts, jsonrpc.WithErrors(c.errors.RPCErrors()))
+	}
+	switch scheme {
+	case "http", "https":
+		if httpClient != nil {
+			opts = append(opts, jsonrpc.WithHTTPClient(httpClient))
+		}
+	case "ws", "wss":
+		// go-jsonrpc dials websockets with the default dialer, so there is no
+		// way to pass the transport settings through.
+		if c.httpClient != nil || c.tlsConfig != nil || c.dial != nil {
+			return nil, errors.New("custom HTTP clients, TLS configs and dialers are only supported for http(s) and unix addresses")
+		}
+	}
+	return opts, nil
+}
+
+// resolveHTTPClient returns the HTTP client to use, or nil if the go-jsonrpc
+// default is fine.
+func (c *config) resolveHTTPClient(tokens *tokenSource) (*http.Client, error) {
+	if c.httpClient == nil && c.tlsConfig == nil && c.timeout == 0 && tokens == nil && c.dialContext() == nil {
+		return nil, nil
+	}
+
+	client := &http.Client{}
+	if c.httpClient != nil {
+		// copy, so the caller's client is left untouched
+		*client = *c.httpClient
+	}
+	if client.Transport == nil {
+		client.Transport = http.DefaultTransport
+	}
+	if c.timeout != 0 {
+		client.Timeout = c.timeout
+	}
+	if dial := c.dialContext(); c.tlsConfig != nil || dial != nil {
+		transport, ok := client.Transport.(*http.Transport)
+		if !ok {
+			return nil, fmt.Errorf("cannot set TLS config or dialer on transport of type %T", client.Transport)
+		}
+		transport = transport.Clone()
+		if c.tlsConfig != nil {
+			transport.TLSClientConfig = c.tlsConfig
+		}
+		if dial != nil {
+			transport.DialContext = dial
+		}
+		client.Transport = transport
+	}
+	if tokens != nil {
+		client.Transport = &tokenTransport{base: client.Transport, tokens: tokens}
+	}
+	return client, nil
+}
+
+// dialContext returns the function connecting to the node, or nil if the
+// transport's default is fine.
+func (c *config) dialContext() DialFunc {
+	if c.socket == "" {
+		return c.dial
+	}
+	dial := c.dial
+	if dial == nil {
+		dial = (&net.Dialer{}).DialContext
+	}
+	socket := c.socket
+	return func(ctx context.Context, _, _ string) (net.Conn, error) {
+		return dial(ctx, "unix", socket)
+	}
+}
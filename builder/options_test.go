@@ -0,0 +1,164 @@
+package clientbuilder
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/filecoin-project/go-jsonrpc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnixSocket(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	socket := filepath.Join(t.TempDir(), "node.sock")
+	l, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+	rpc := jsonrpc.NewServer()
+	rpc.Register("test", testHandler{})
+	srv := &http.Server{Handler: rpc, ReadHeaderTimeout: time.Second}
+	go func() { _ = srv.Serve(l) }()
+	defer srv.Close()
+
+	client, closer, err := Build[testGroup](ctx, "unix://"+socket)
+	require.NoError(t, err)
+	defer closer()
+	v, err := client.Test.Get(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, v)
+
+	_, _, err = Build[testGroup](ctx, "unix://")
+	require.Error(t, err)
+}
+
+func TestWithDialer(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	rpc := jsonrpc.NewServer()
+	rpc.Register("test", testHandler{})
+	srv := httptest.NewServer(rpc)
+	defer srv.Close()
+
+	var dials atomic.Int32
+	dial := func(ctx context.Context, network, addr string) (net.Conn, error) {
+		dials.Add(1)
+		return (&net.Dialer{}).DialContext(ctx, network, addr)
+	}
+	client, closer, err := Build[testGroup](ctx, srv.URL, WithDialer(dial))
+	require.NoError(t, err)
+	defer closer()
+	v, err := client.Test.Get(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, v)
+	require.Positive(t, dials.Load())
+
+	_, _, err = Build[testGroup](ctx, "ws"+strings.TrimPrefix(srv.URL, "http"), WithDialer(dial))
+	require.ErrorContains(t, err, "only supported for http(s) and unix addresses")
+}
+
+func TestResolveHTTPClient(t *testing.T) {
+	// no option needs a custom client
+	client, err := newConfig("", nil).resolveHTTPClient(nil)
+	require.NoError(t, err)
+	require.Nil(t, client)
+
+	base := &http.Client{}
+	client, err = newConfig("", []Option{WithHTTPClient(base), WithTimeout(time.Second)}).resolveHTTPClient(nil)
+	require.NoError(t, err)
+	require.Equal(t, time.Second, client.Timeout)
+	require.Zero(t, base.Timeout, "the caller's client is left untouched")
+	require.Equal(t, http.DefaultTransport, client.Transport)
+
+	tlsConfig := &tls.Config{ServerName: "node", MinVersion: tls.VersionTLS12}
+	client, err = newConfig("", []Option{WithTLSConfig(tlsConfig)}).resolveHTTPClient(nil)
+	require.NoError(t, err)
+	transport, ok := client.Transport.(*http.Transport)
+	require.True(t, ok)
+	require.Same(t, tlsConfig, transport.TLSClientConfig)
+	require.NotSame(t, http.DefaultTransport, transport)
+
+	custom := &http.Client{Transport: roundTripperFunc(http.DefaultTransport.RoundTrip)}
+	_, err = newConfig("", []Option{WithHTTPClient(custom), WithTLSConfig(tlsConfig)}).resolveHTTPClient(nil)
+	require.ErrorContains(t, err, "cannot set TLS config")
+
+	cfg := newConfig("", []Option{WithHeader("X-Api-Key", "a"), WithHeader("X-Api-Key", "b")})
+	require.Equal(t, []string{"a", "b"}, cfg.header.Values("X-Api-Key"))
+
+	for _, opt := range []Option{WithHTTPClient(base), WithTLSConfig(tlsConfig)} {
+		_, err = newConfig("", []Option{opt}).options("ws", nil)
+		require.ErrorContains(t, err, "only supported for http(s) and unix addresses")
+	}
+}
+
+// TestOptions ensures that the options reach the requests sent to the node.
+func TestOptions(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	rpc := jsonrpc.NewServer()
+	rpc.Register("test", testHandler{})
+	var header http.Header
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Clone()
+		rpc.ServeHTTP(w, r)
+	}))
+	srv.Config.ErrorLog = log.New(io.Discard, "", 0)
+	srv.StartTLS()
+	defer srv.Close()
+
+	// the server's certificate is unknown to the default transport
+	client, closer, err := Build[testGroup](ctx, srv.URL)
+	require.NoError(t, err)
+	_, err = client.Test.Get(ctx)
+	require.Error(t, err)
+	closer()
+
+	pool := x509.NewCertPool()
+	pool.AddCert(srv.Certificate())
+	client, closer, err = Build[testGroup](ctx, srv.URL,
+		WithToken("token"),
+		WithTLSConfig(&tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}),
+		WithHeader("X-Api-Key", "key"),
+		WithTimeout(5*time.Second),
+	)
+	require.NoError(t, err)
+	defer closer()
+	v, err := client.Test.Get(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, v)
+	require.Equal(t, "key", header.Get("X-Api-Key"))
+	require.Equal(t, "Bearer token", header.Get(AuthKey))
+
+	var trips atomic.Int32
+	httpClient := srv.Client()
+	transport := httpClient.Transport
+	httpClient.Transport = roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		trips.Add(1)
+		return transport.RoundTrip(r)
+	})
+	client, closer, err = Build[testGroup](ctx, srv.URL, WithHTTPClient(httpClient))
+	require.NoError(t, err)
+	defer closer()
+	_, err = client.Test.Get(ctx)
+	require.NoError(t, err)
+	require.Positive(t, trips.Load())
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
@@ -0,0 +1,137 @@
+package clientbuilder
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/filecoin-project/go-jsonrpc/auth"
+)
+
+// ErrPermissionDenied is returned, wrapped in a *PermissionError, by calls to
+// methods that the client's token does not permit.
+var ErrPermissionDenied = errors.New("permission denied")
+
+// PermissionError reports a call that was rejected on the client side, as it
+// would be rejected by the node.
+type PermissionError struct {
+	Method   string
+	Required auth.Permission
+	Granted  Permissions
+}
+
+func (e *PermissionError) Error() string {
+	return fmt.Sprintf("%s: method %s requires %q, token grants %v", ErrPermissionDenied, e.Method, e.Required, e.Granted)
+}
+
+func (e *PermissionError) Unwrap() error {
+	return ErrPermissionDenied
+}
+
+// Permissions is the set of permissions granted to a token.
+type Permissions []auth.Permission
+
+// Allows reports whether the permissions are sufficient to call the method.
+func (p Permissions) Allows(m Method) bool {
+	for _, perm := range p {
+		if perm == m.Perm {
+			return true
+		}
+	}
+	return false
+}
+
+// PermissionsFromToken returns the permissions granted by a JWT issued by
+// celestia-node. The signature is not verified, as only the node holds the
+// key, so the result is only good for failing early; the node still enforces
+// the permissions.
+func PermissionsFromToken(token string) (Permissions, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("token is not a JWT")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("decoding token payload: %w", err)
+	}
+
+	var claims struct {
+		Allow []auth.Permission `json:"Allow"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, fmt.Errorf("decoding token payload: %w", err)
+	}
+	if claims.Allow == nil {
+		return nil, errors.New("token does not list its permissions")
+	}
+	return claims.Allow, nil
+}
+
+// WithPermissions sets the permissions to check calls against, instead of
+// learning them from the token.
+func WithPermissions(perms ...auth.Permission) Option {
+	return func(c *config) {
+		c.perms = perms
+	}
+}
+
+// WithoutPermissionCheck leaves checking permissions to the node.
+func WithoutPermissionCheck() Option {
+	return func(c *config) {
+		c.noPermCheck = true
+	}
+}
+
+// Permissions returns the permissions to check calls against, or nil if they
+// are unknown or the check is disabled. Unless set explicitly, they are
+// decoded from the current token, falling back to verify, if not nil, should
+// the token not be decodable.
+func (d *Dialer) Permissions(
+	ctx context.Context,
+	verify func(ctx context.Context, token string) ([]auth.Permission, error),
+) Permissions {
+	switch {
+	case d.cfg.noPermCheck:
+		return nil
+	case d.cfg.perms != nil:
+		return d.cfg.perms
+	}
+
+	token := d.token()
+	if token == "" {
+		return nil
+	}
+	if perms, err := PermissionsFromToken(token); err == nil {
+		return perms
+	}
+	if verify != nil {
+		if perms, err := verify(ctx, token); err == nil {
+			return perms
+		}
+	}
+	return nil
+}
+
+// CheckPermissions makes calls to the methods of client that perms does not
+// allow fail with a *PermissionError without reaching the node.
+func CheckPermissions(client interface{}, perms Permissions) error {
+	methods, err := Methods(client)
+	if err != nil {
+		return err
+	}
+	for _, m := range methods {
+		if perms.Allows(m) {
+			continue
+		}
+		permErr := &PermissionError{Method: m.String(), Required: m.Perm, Granted: perms}
+		results := m.ErrorResults(permErr)
+		m.Value(client).Set(reflect.MakeFunc(m.Type, func([]reflect.Value) []reflect.Value {
+			return results
+		}))
+	}
+	return nil
+}
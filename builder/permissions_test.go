@@ -0,0 +1,44 @@
+package clientbuilder
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"github.com/filecoin-project/go-jsonrpc/auth"
+	"github.com/stretchr/testify/require"
+)
+
+type testModule struct {
+	Get func(context.Context) (int, error) `perm:"read"`
+	Set func(context.Context, int) error   `perm:"write"`
+}
+
+type testClient struct {
+	Test testModule
+}
+
+func TestCheckPermissions(t *testing.T) {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(`{"Allow":["public","read"]}`))
+	perms, err := PermissionsFromToken("header." + payload + ".signature")
+	require.NoError(t, err)
+	require.Equal(t, Permissions{"public", "read"}, perms)
+
+	client := &testClient{Test: testModule{
+		Get: func(context.Context) (int, error) { return 1, nil },
+		Set: func(context.Context, int) error { return nil },
+	}}
+	require.NoError(t, CheckPermissions(client, perms))
+
+	v, err := client.Test.Get(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, v)
+
+	err = client.Test.Set(context.Background(), 2)
+	require.True(t, errors.Is(err, ErrPermissionDenied))
+	var permErr *PermissionError
+	require.True(t, errors.As(err, &permErr))
+	require.Equal(t, "test.Set", permErr.Method)
+	require.Equal(t, auth.Permission("write"), permErr.Required)
+}
@@ -0,0 +1,253 @@
+package clientbuilder
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/filecoin-project/go-jsonrpc"
+)
+
+// ErrorClass classifies the errors returned by calls to the node.
+type ErrorClass int
+
+const (
+	// ClassPermanent errors will not go away by trying again.
+	ClassPermanent ErrorClass = iota
+	// ClassConnection errors are failures of the connection to the node, e.g.
+	// a reset websocket.
+	ClassConnection
+	// ClassTimeout errors are timeouts on the node's side.
+	ClassTimeout
+	// ClassNotSynced errors are returned by a node that is still syncing.
+	ClassNotSynced
+	// ClassMempoolFull errors are returned when the mempool is out of space.
+	ClassMempoolFull
+)
+
+func (c ErrorClass) String() string {
+	switch c {
+	case ClassConnection:
+		return "connection"
+	case ClassTimeout:
+		return "timeout"
+	case ClassNotSynced:
+		return "not synced"
+	case ClassMempoolFull:
+		return "mempool full"
+	default:
+		return "permanent"
+	}
+}
+
+// Transient reports whether errors of the class may go away on their own.
+func (c ErrorClass) Transient() bool {
+	return c != ClassPermanent
+}
+
+// Classify determines the class of err. Errors from the node only carry their
+// message, so they are classified by it.
+func Classify(err error) ErrorClass {
+	var transient *TransientError
+	var connErr *jsonrpc.RPCConnectionError
+	switch {
+	case err == nil:
+		return ClassPermanent
+	case errors.As(err, &transient):
+		return transient.Class
+	case errors.As(err, &connErr):
+		return ClassConnection
+	}
+
+	msg := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(msg, "websocket connection closed"),
+		strings.Contains(msg, "connection reset"),
+		strings.Contains(msg, "connection refused"):
+		return ClassConnection
+	case strings.Contains(msg, "context deadline exceeded"):
+		return ClassTimeout
+	case strings.Contains(msg, "not synced"),
+		strings.Contains(msg, "syncer is syncing"):
+		return ClassNotSynced
+	case strings.Contains(msg, "mempool is full"):
+		return ClassMempoolFull
+	default:
+		return ClassPermanent
+	}
+}
+
+// TransientError marks an error that may go away by trying again. Methods with
+// a retry policy return it once retrying is exhausted or the error is not
+// retryable by the policy.
+type TransientError struct {
+	Class ErrorClass
+	// Attempts is the number of calls that were made.
+	Attempts int
+	Err      error
+}
+
+func (e *TransientError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *TransientError) Unwrap() error {
+	return e.Err
+}
+
+// IsTransient reports whether err may go away by trying again.
+func IsTransient(err error) bool {
+	return Classify(err).Transient()
+}
+
+// RetryPolicy configures how failed calls are retried.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of calls, including the first one.
+	MaxAttempts int
+	// MinBackoff and MaxBackoff bound the exponential backoff between calls.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+	// Jitter is the fraction, between 0 and 1, of the backoff that is
+	// randomized.
+	Jitter float64
+	// Retryable decides whether a failed call is retried. Defaults to
+	// IsTransient.
+	Retryable func(error) bool
+}
+
+// DefaultRetryPolicy retries transient errors up to 5 times within a few
+// seconds.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 5,
+		MinBackoff:  100 * time.Millisecond,
+		MaxBackoff:  3 * time.Second,
+		Jitter:      0.2,
+	}
+}
+
+// backoff returns the delay before the given retry, counting from 1.
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	delay := p.MinBackoff
+	for i := 1; i < retry && delay < p.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > p.MaxBackoff {
+		delay = p.MaxBackoff
+	}
+	if p.Jitter > 0 {
+		//nolint:gosec
+		delay -= time.Duration(p.Jitter * rand.Float64() * float64(delay))
+	}
+	return delay
+}
+
+func (p RetryPolicy) retryable(err error) bool {
+	if p.Retryable != nil {
+		return p.Retryable(err)
+	}
+	return IsTransient(err)
+}
+
+// retryPolicies holds the configured policies by scope.
+type retryPolicies struct {
+	all      *RetryPolicy
+	byModule map[string]RetryPolicy
+	byMethod map[string]RetryPolicy
+}
+
+// policy returns the policy for the method, if any. Only read methods are
+// covered by the default and module policies; anything else is retried only
+// if the method has a policy of its own, as retrying writes might e.g. submit
+// a transaction twice.
+func (r *retryPolicies) policy(m Method) (RetryPolicy, bool) {
+	if p, ok := r.byMethod[m.String()]; ok {
+		return p, true
+	}
+	if m.Perm != PermRead {
+		return RetryPolicy{}, false
+	}
+	if p, ok := r.byModule[m.Namespace]; ok {
+		return p, true
+	}
+	if r.all != nil {
+		return *r.all, true
+	}
+	return RetryPolicy{}, false
+}
+
+// WithRetry retries calls to read methods according to policy.
+func WithRetry(policy RetryPolicy) Option {
+	return func(c *config) {
+		c.retry.all = &policy
+	}
+}
+
+// WithModuleRetry retries calls to the read methods of the namespace, e.g.
+// "header", according to policy, overriding WithRetry.
+func WithModuleRetry(namespace string, policy RetryPolicy) Option {
+	return func(c *config) {
+		if c.retry.byModule == nil {
+			c.retry.byModule = make(map[string]RetryPolicy)
+		}
+		c.retry.byModule[namespace] = policy
+	}
+}
+
+// WithMethodRetry retries calls to the method, e.g. "blob.Get", according to
+// policy, overriding WithRetry and WithModuleRetry. This is the only way to
+// retry methods that require more than the read permission, so make sure the
+// method is safe to call twice.
+func WithMethodRetry(method string, policy RetryPolicy) Option {
+	return func(c *config) {
+		if c.retry.byMethod == nil {
+			c.retry.byMethod = make(map[string]RetryPolicy)
+		}
+		c.retry.byMethod[method] = policy
+	}
+}
+
+// retry wraps a method so that failed calls are retried according to policy.
+func retry(m Method, policy RetryPolicy, fn interface{}) reflect.Value {
+	// keep the original function, as the field is about to be overwritten
+	next := reflect.ValueOf(fn)
+	return reflect.MakeFunc(m.Type, func(args []reflect.Value) []reflect.Value {
+		ctx := m.Context(args)
+		for attempt := 1; ; attempt++ {
+			var results []reflect.Value
+			if m.Type.IsVariadic() {
+				results = next.CallSlice(args)
+			} else {
+				results = next.Call(args)
+			}
+			err := m.Err(results)
+			if err == nil || ctx.Err() != nil {
+				// the caller gave up, so its error is not worth classifying
+				return results
+			}
+
+			class := Classify(err)
+			if attempt >= policy.MaxAttempts || !policy.retryable(err) || !sleep(ctx, policy.backoff(attempt)) {
+				if class.Transient() {
+					results = m.ErrorResults(&TransientError{Class: class, Attempts: attempt, Err: err})
+				}
+				return results
+			}
+		}
+	})
+}
+
+// sleep waits for d. It returns false if ctx is done first.
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,123 @@
+package clientbuilder
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/filecoin-project/go-jsonrpc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		err  error
+		want ErrorClass
+	}{
+		{&jsonrpc.RPCConnectionError{}, ClassConnection},
+		{errors.New("websocket connection closed"), ClassConnection},
+		{errors.New("getting shares: context deadline exceeded"), ClassTimeout},
+		{errors.New("header: not synced yet"), ClassNotSynced},
+		{errors.New("rpc error: mempool is full: number of txs 5000"), ClassMempoolFull},
+		{errors.New("blob: not found"), ClassPermanent},
+	}
+	for _, tt := range tests {
+		require.Equal(t, tt.want, Classify(tt.err), tt.err.Error())
+	}
+}
+
+func TestRetry(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+	d := &Dialer{cfg: newConfig("", []Option{WithRetry(policy)})}
+
+	var gets, sets int
+	client := &testClient{Test: testModule{
+		Get: func(context.Context) (int, error) {
+			gets++
+			if gets < 3 {
+				return 0, errors.New("websocket connection closed")
+			}
+			return gets, nil
+		},
+		Set: func(context.Context, int) error {
+			sets++
+			return errors.New("mempool is full")
+		},
+	}}
+	require.NoError(t, d.Wrap(client))
+
+	// reads are retried until they succeed
+	v, err := client.Test.Get(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 3, v)
+
+	// writes are never retried without a policy of their own
+	err = client.Test.Set(context.Background(), 1)
+	require.Equal(t, 1, sets)
+	require.Equal(t, ClassMempoolFull, Classify(err))
+}
+
+func TestRetry_MethodPolicy(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 2, MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+	d := &Dialer{cfg: newConfig("", []Option{
+		WithModuleRetry("test", RetryPolicy{MaxAttempts: 1}),
+		WithMethodRetry("test.Set", policy),
+	})}
+
+	var gets, sets int
+	client := &testClient{Test: testModule{
+		Get: func(context.Context) (int, error) {
+			gets++
+			return 0, errors.New("not synced")
+		},
+		Set: func(context.Context, int) error {
+			sets++
+			return errors.New("not synced")
+		},
+	}}
+	require.NoError(t, d.Wrap(client))
+
+	_, err := client.Test.Get(context.Background())
+	require.True(t, IsTransient(err))
+	require.Equal(t, 1, gets)
+
+	err = client.Test.Set(context.Background(), 1)
+	var transient *TransientError
+	require.True(t, errors.As(err, &transient))
+	require.Equal(t, 2, transient.Attempts)
+	require.Equal(t, 2, sets)
+}
+
+type variadicClient struct {
+	Test struct {
+		Sum func(context.Context, ...int) (int, error) `perm:"read"`
+	}
+}
+
+// TestRetry_Variadic ensures that the variadic arguments of retried calls
+// are passed on as they were given.
+func TestRetry_Variadic(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 2, MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+	d := &Dialer{cfg: newConfig("", []Option{WithRetry(policy)})}
+
+	var calls int
+	client := &variadicClient{}
+	client.Test.Sum = func(_ context.Context, xs ...int) (int, error) {
+		calls++
+		if calls == 1 {
+			return 0, errors.New("websocket connection closed")
+		}
+		sum := 0
+		for _, x := range xs {
+			sum += x
+		}
+		return sum, nil
+	}
+	require.NoError(t, d.Wrap(client))
+
+	sum, err := client.Test.Sum(context.Background(), 1, 2, 3)
+	require.NoError(t, err)
+	require.Equal(t, 6, sum)
+	require.Equal(t, 2, calls)
+}
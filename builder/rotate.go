@@ -0,0 +1,220 @@
+package clientbuilder
+
+import (
+	"context"
+	"reflect"
+	"sync"
+
+	"github.com/filecoin-project/go-jsonrpc"
+)
+
+// generation is one websocket connection of a rotating module, authenticated
+// with the token that was current when it was dialed.
+type generation struct {
+	module reflect.Value
+	closer jsonrpc.ClientCloser
+
+	// active counts in-flight calls and open subscriptions.
+	active  int
+	retired bool
+	once    sync.Once
+	// done is closed once the connection is closed.
+	done chan struct{}
+}
+
+func (g *generation) close() {
+	g.once.Do(func() {
+		close(g.done)
+		g.closer()
+	})
+}
+
+// rotatingModule backs the function fields of a module with a websocket
+// connection that is replaced whenever the token changes.
+type rotatingModule struct {
+	dialer    *Dialer
+	namespace string
+	typ       reflect.Type
+
+	mu      sync.Mutex
+	current *generation
+	old     []*generation
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func (d *Dialer) dialRotating(ctx context.Context, namespace string, module interface{}) (jsonrpc.ClientCloser, error) {
+	v := reflect.ValueOf(module)
+	r := &rotatingModule{
+		dialer:    d,
+		namespace: namespace,
+		typ:       v.Type().Elem(),
+		done:      make(chan struct{}),
+	}
+
+	// subscribe before dialing, so no change goes unnoticed
+	changes := d.tokens.subscribe()
+	gen, err := r.dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.current = gen
+
+	for i := 0; i < r.typ.NumField(); i++ {
+		if r.typ.Field(i).Type.Kind() != reflect.Func {
+			continue
+		}
+		v.Elem().Field(i).Set(r.dispatch(i))
+	}
+
+	ctx, r.cancel = context.WithCancel(ctx)
+	go r.rotate(ctx, changes)
+	return r.close, nil
+}
+
+func (r *rotatingModule) dial(ctx context.Context) (*generation, error) {
+	module := reflect.New(r.typ)
+	closer, err := jsonrpc.NewMergeClient(
+		ctx,
+		r.dialer.addr,
+		r.namespace,
+		[]interface{}{module.Interface()},
+		r.dialer.header(),
+		r.dialer.rpcOpts...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &generation{module: module.Elem(), closer: closer, done: make(chan struct{})}, nil
+}
+
+// rotate replaces the current connection whenever the token changes.
+func (r *rotatingModule) rotate(ctx context.Context, changes <-chan struct{}) {
+	defer close(r.done)
+	for {
+		select {
+		case <-changes:
+		case <-ctx.Done():
+			return
+		}
+
+		gen, err := r.dial(ctx)
+		if err != nil {
+			// the old connection keeps serving until the next change
+			continue
+		}
+
+		r.mu.Lock()
+		old := r.current
+		r.current = gen
+		old.retired = true
+		idle := old.active == 0
+		if !idle {
+			r.old = append(r.old, old)
+		}
+		r.mu.Unlock()
+
+		if idle {
+			old.close()
+		}
+	}
+}
+
+func (r *rotatingModule) acquire() *generation {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.current.active++
+	return r.current
+}
+
+func (r *rotatingModule) release(gen *generation) {
+	r.mu.Lock()
+	gen.active--
+	if !gen.retired || gen.active > 0 {
+		r.mu.Unlock()
+		return
+	}
+	for i, old := range r.old {
+		if old == gen {
+			r.old = append(r.old[:i], r.old[i+1:]...)
+			break
+		}
+	}
+	r.mu.Unlock()
+
+	gen.close()
+}
+
+// dispatch returns a function calling the i-th method on the current
+// connection.
+func (r *rotatingModule) dispatch(i int) reflect.Value {
+	ftyp := r.typ.Field(i).Type
+	subscribes := ftyp.NumOut() == 2 && ftyp.Out(0).Kind() == reflect.Chan
+
+	takesCtx := ftyp.NumIn() > 0 && ftyp.In(0) == contextType
+
+	return reflect.MakeFunc(ftyp, func(args []reflect.Value) []reflect.Value {
+		gen := r.acquire()
+		var results []reflect.Value
+		if ftyp.IsVariadic() {
+			results = gen.module.Field(i).CallSlice(args)
+		} else {
+			results = gen.module.Field(i).Call(args)
+		}
+		if !subscribes || !results[1].IsNil() {
+			r.release(gen)
+			return results
+		}
+		// keep the connection until the subscription ends
+		ctx := context.Background()
+		if takesCtx && !args[0].IsNil() {
+			ctx = args[0].Interface().(context.Context)
+		}
+		results[0] = r.forward(ctx, gen, results[0], ftyp.Out(0))
+		return results
+	})
+}
+
+// forward relays a subscription channel, releasing gen once it is closed.
+// It also gives up once the ctx of the call is done or the connection is
+// closed, so that a subscriber that stopped reading does not keep a retired
+// connection open.
+func (r *rotatingModule) forward(ctx context.Context, gen *generation, in reflect.Value, typ reflect.Type) reflect.Value {
+	out := reflect.MakeChan(reflect.ChanOf(reflect.BothDir, typ.Elem()), 0)
+	go func() {
+		defer r.release(gen)
+		defer out.Close()
+		stop := []reflect.SelectCase{
+			{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())},
+			{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(gen.done)},
+		}
+		recv := append([]reflect.SelectCase{{Dir: reflect.SelectRecv, Chan: in}}, stop...)
+		send := append([]reflect.SelectCase{{Dir: reflect.SelectSend, Chan: out}}, stop...)
+		for {
+			chosen, v, ok := reflect.Select(recv)
+			if chosen != 0 || !ok {
+				return
+			}
+			send[0].Send = v
+			if chosen, _, _ := reflect.Select(send); chosen != 0 {
+				return
+			}
+		}
+	}()
+	return out.Convert(typ)
+}
+
+func (r *rotatingModule) close() {
+	r.cancel()
+	<-r.done
+
+	r.mu.Lock()
+	gens := append(r.old, r.current)
+	r.old = nil
+	r.mu.Unlock()
+
+	for _, gen := range gens {
+		gen.close()
+	}
+}
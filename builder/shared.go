@@ -0,0 +1,126 @@
+package clientbuilder
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/filecoin-project/go-jsonrpc"
+)
+
+// WithLazyDial defers connecting to the node until the first call, instead of
+// connecting when the client is created. Failing to connect fails that call,
+// and the next call tries again.
+func WithLazyDial() Option {
+	return func(c *config) {
+		c.lazy = true
+	}
+}
+
+// sharedConn is a single connection serving the methods of every module of a
+// client. go-jsonrpc prefixes all methods of a connection with the same
+// namespace, so the methods are gathered in a flat struct whose fields name
+// their full method, e.g. "blob.Get", in their rpc_method tag.
+type sharedConn struct {
+	dialer  *Dialer
+	methods []Method
+	flat    reflect.Value
+
+	mu     sync.Mutex
+	closer jsonrpc.ClientCloser
+	closed bool
+	// ctx bounds lazily dialed connections, which outlive the call that
+	// dialed them.
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// DialAll fills in the methods of every module of client, which must be a
+// pointer to a struct of modules, with calls over a single connection. Modules
+// are named by their lowercased field name.
+func (d *Dialer) DialAll(ctx context.Context, client interface{}) (jsonrpc.ClientCloser, error) {
+	methods, err := Methods(client)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make([]reflect.StructField, len(methods))
+	for i, m := range methods {
+		fields[i] = reflect.StructField{
+			Name: fmt.Sprintf("M%d", i),
+			Type: m.Type,
+			Tag:  reflect.StructTag(fmt.Sprintf(`rpc_method:%q`, m.String())),
+		}
+	}
+	c := &sharedConn{
+		dialer:  d,
+		methods: methods,
+		flat:    reflect.New(reflect.StructOf(fields)),
+	}
+
+	if !d.cfg.lazy {
+		if err := c.dial(ctx); err != nil {
+			return nil, err
+		}
+		for i, m := range methods {
+			m.Value(client).Set(c.flat.Elem().Field(i))
+		}
+		return c.close, nil
+	}
+
+	c.ctx, c.cancel = context.WithCancel(context.Background())
+	for i, m := range methods {
+		m.Value(client).Set(c.lazyMethod(i))
+	}
+	return c.close, nil
+}
+
+// dial connects, unless already connected. The connection is bound to ctx.
+func (c *sharedConn) dial(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	switch {
+	case c.closed:
+		return fmt.Errorf("client is closed")
+	case c.closer != nil:
+		return nil
+	}
+
+	closer, err := c.dialer.Dial(ctx, "", c.flat.Interface())
+	if err != nil {
+		return err
+	}
+	c.closer = closer
+	return nil
+}
+
+// lazyMethod returns a function connecting on first use before calling the
+// i-th method.
+func (c *sharedConn) lazyMethod(i int) reflect.Value {
+	m := c.methods[i]
+	return reflect.MakeFunc(m.Type, func(args []reflect.Value) []reflect.Value {
+		if err := c.dial(c.ctx); err != nil {
+			return m.ErrorResults(err)
+		}
+		fn := c.flat.Elem().Field(i)
+		if m.Type.IsVariadic() {
+			return fn.CallSlice(args)
+		}
+		return fn.Call(args)
+	})
+}
+
+func (c *sharedConn) close() {
+	c.mu.Lock()
+	closer := c.closer
+	c.closer, c.closed = nil, true
+	c.mu.Unlock()
+
+	if c.cancel != nil {
+		c.cancel()
+	}
+	if closer != nil {
+		closer()
+	}
+}
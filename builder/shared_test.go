@@ -0,0 +1,62 @@
+package clientbuilder
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/filecoin-project/go-jsonrpc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDialAll(t *testing.T) {
+	for _, lazy := range []bool{false, true} {
+		lazy := lazy
+		t.Run(map[bool]string{false: "eager", true: "lazy"}[lazy], func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			rpc := jsonrpc.NewServer()
+			rpc.Register("test", testHandler{})
+			rpc.Register("other", testHandler{})
+
+			var conns atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				conns.Add(1)
+				rpc.ServeHTTP(w, r)
+			}))
+			defer srv.Close()
+
+			var opts []Option
+			if lazy {
+				opts = append(opts, WithLazyDial())
+			}
+			dialer, err := NewDialer(ctx, "ws"+strings.TrimPrefix(srv.URL, "http"), "", opts...)
+			require.NoError(t, err)
+			defer dialer.Close()
+
+			var client struct {
+				Test  testModule
+				Other testModule
+			}
+			closer, err := dialer.DialAll(ctx, &client)
+			require.NoError(t, err)
+			defer closer()
+			if lazy {
+				require.Zero(t, conns.Load())
+			}
+
+			v, err := client.Test.Get(ctx)
+			require.NoError(t, err)
+			require.Equal(t, 1, v)
+			v, err = client.Other.Get(ctx)
+			require.NoError(t, err)
+			require.Equal(t, 1, v)
+			require.EqualValues(t, 1, conns.Load())
+		})
+	}
+}
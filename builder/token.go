@@ -0,0 +1,195 @@
+package clientbuilder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/filecoin-project/go-jsonrpc/auth"
+)
+
+// TokenProvider supplies the token used to authenticate with the node. It is
+// asked for the token periodically, so that rotated tokens are picked up.
+type TokenProvider interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// TokenProviderFunc adapts a function to the TokenProvider interface.
+type TokenProviderFunc func(ctx context.Context) (string, error)
+
+func (f TokenProviderFunc) Token(ctx context.Context) (string, error) {
+	return f(ctx)
+}
+
+// StaticToken provides the same token forever.
+func StaticToken(token string) TokenProvider {
+	return TokenProviderFunc(func(context.Context) (string, error) {
+		return token, nil
+	})
+}
+
+// EnvToken provides the token stored in the named environment variable.
+func EnvToken(name string) TokenProvider {
+	return TokenProviderFunc(func(context.Context) (string, error) {
+		token := strings.TrimSpace(os.Getenv(name))
+		if token == "" {
+			return "", fmt.Errorf("environment variable %s is not set", name)
+		}
+		return token, nil
+	})
+}
+
+// FileToken provides the token stored in the file at path, e.g. one written by
+// `celestia <node-type> auth`.
+func FileToken(path string) TokenProvider {
+	return TokenProviderFunc(func(context.Context) (string, error) {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return "", err
+		}
+		token := strings.TrimSpace(string(raw))
+		if token == "" {
+			return "", fmt.Errorf("token file %s is empty", path)
+		}
+		return token, nil
+	})
+}
+
+// MintedToken provides a freshly minted token with the given permissions every
+// time it is asked, so that each token is only used until the next refresh.
+// mint is usually Node.AuthNew of a client holding an admin token.
+func MintedToken(
+	mint func(ctx context.Context, perms []auth.Permission) ([]byte, error),
+	perms ...auth.Permission,
+) TokenProvider {
+	return TokenProviderFunc(func(ctx context.Context) (string, error) {
+		token, err := mint(ctx, perms)
+		if err != nil {
+			return "", fmt.Errorf("minting token: %w", err)
+		}
+		return string(token), nil
+	})
+}
+
+// WithTokenProvider authenticates with the token supplied by provider instead
+// of the static token, asking it for the current token every interval.
+//
+// Requests over http(s) always carry the current token. Websocket connections
+// only present it when connecting, so once the token changes they are replaced
+// by new ones for subsequent calls; the old connections are kept until their
+// in-flight calls and subscriptions are done.
+func WithTokenProvider(provider TokenProvider, interval time.Duration) Option {
+	return func(c *config) {
+		c.tokenProvider = provider
+		c.tokenRefresh = interval
+	}
+}
+
+// tokenSource keeps the current token of a TokenProvider and notifies
+// subscribers when it changes.
+type tokenSource struct {
+	provider TokenProvider
+	interval time.Duration
+
+	mu      sync.Mutex
+	token   string
+	changes []chan struct{}
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func newTokenSource(ctx context.Context, provider TokenProvider, interval time.Duration) (*tokenSource, error) {
+	if interval <= 0 {
+		return nil, errors.New("token refresh interval must be positive")
+	}
+	token, err := provider.Token(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting token: %w", err)
+	}
+
+	runCtx, cancel := context.WithCancel(context.Background())
+	s := &tokenSource{
+		provider: provider,
+		interval: interval,
+		token:    token,
+		cancel:   cancel,
+		done:     make(chan struct{}),
+	}
+	go s.run(runCtx)
+	return s, nil
+}
+
+func (s *tokenSource) run(ctx context.Context) {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+
+		token, err := s.provider.Token(ctx)
+		if err != nil {
+			// keep using the current token until the provider recovers
+			continue
+		}
+
+		s.mu.Lock()
+		if token != s.token {
+			s.token = token
+			for _, ch := range s.changes {
+				select {
+				case ch <- struct{}{}:
+				default:
+				}
+			}
+		}
+		s.mu.Unlock()
+	}
+}
+
+// current returns the current token.
+func (s *tokenSource) current() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.token
+}
+
+// subscribe returns a channel that is signaled whenever the token changes.
+func (s *tokenSource) subscribe() <-chan struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ch := make(chan struct{}, 1)
+	s.changes = append(s.changes, ch)
+	return ch
+}
+
+func (s *tokenSource) close() {
+	s.cancel()
+	<-s.done
+}
+
+// tokenTransport sets the current token on every request.
+type tokenTransport struct {
+	base   http.RoundTripper
+	tokens *tokenSource
+}
+
+func (t *tokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set(AuthKey, bearer(t.tokens.current()))
+	return t.base.RoundTrip(req)
+}
+
+func bearer(token string) string {
+	return fmt.Sprintf("Bearer %s", token)
+}
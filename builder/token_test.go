@@ -0,0 +1,196 @@
+package clientbuilder
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/filecoin-project/go-jsonrpc"
+	"github.com/stretchr/testify/require"
+)
+
+type testHandler struct{}
+
+func (testHandler) Get(context.Context) (int, error) {
+	return 1, nil
+}
+
+// TestTokenRotation ensures that websocket connections are re-established with
+// the new token once it changes.
+func TestTokenRotation(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	rpc := jsonrpc.NewServer()
+	rpc.Register("test", testHandler{})
+
+	var (
+		mu    sync.Mutex
+		seen  []string
+		token = "first"
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		seen = append(seen, r.Header.Get(AuthKey))
+		mu.Unlock()
+		rpc.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+
+	provider := TokenProviderFunc(func(context.Context) (string, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		return token, nil
+	})
+	dialer, err := NewDialer(ctx, "ws"+strings.TrimPrefix(srv.URL, "http"), "",
+		WithTokenProvider(provider, 10*time.Millisecond))
+	require.NoError(t, err)
+	defer dialer.Close()
+
+	var module testModule
+	closer, err := dialer.Dial(ctx, "test", &module)
+	require.NoError(t, err)
+	defer closer()
+
+	v, err := module.Get(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, v)
+
+	mu.Lock()
+	require.Equal(t, []string{"Bearer first"}, seen)
+	token = "second"
+	mu.Unlock()
+
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return seen[len(seen)-1] == "Bearer second"
+	}, 5*time.Second, 10*time.Millisecond)
+
+	v, err = module.Get(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, v)
+}
+
+type subHandler struct{}
+
+func (subHandler) Sub(ctx context.Context) (<-chan int, error) {
+	ch := make(chan int)
+	go func() {
+		defer close(ch)
+		for i := 0; ; i++ {
+			select {
+			case ch <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch, nil
+}
+
+type subModule struct {
+	Sub func(context.Context) (<-chan int, error)
+}
+
+// TestTokenRotation_AbandonedSubscription ensures that the retired
+// connection of a subscription whose subscriber stopped reading is closed
+// once the subscription's ctx is done.
+func TestTokenRotation_AbandonedSubscription(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	rpc := jsonrpc.NewServer()
+	rpc.Register("test", subHandler{})
+	var open atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// websocket connections are served until they are closed
+		open.Add(1)
+		defer open.Add(-1)
+		rpc.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+
+	var mu sync.Mutex
+	token := "first"
+	provider := TokenProviderFunc(func(context.Context) (string, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		return token, nil
+	})
+	dialer, err := NewDialer(ctx, "ws"+strings.TrimPrefix(srv.URL, "http"), "",
+		WithTokenProvider(provider, 10*time.Millisecond))
+	require.NoError(t, err)
+	defer dialer.Close()
+
+	var module subModule
+	closer, err := dialer.Dial(ctx, "test", &module)
+	require.NoError(t, err)
+	defer closer()
+
+	subCtx, subCancel := context.WithCancel(ctx)
+	ch, err := module.Sub(subCtx)
+	require.NoError(t, err)
+	// read once, then abandon the channel
+	<-ch
+
+	mu.Lock()
+	token = "second"
+	mu.Unlock()
+	require.Eventually(t, func() bool { return open.Load() == 2 }, 5*time.Second, 10*time.Millisecond)
+
+	subCancel()
+	require.Eventually(t, func() bool { return open.Load() == 1 }, 5*time.Second, 10*time.Millisecond)
+}
+
+type variadicModule struct {
+	Sum   func(context.Context, ...int) (int, error)
+	Ticks func() (<-chan int, error)
+}
+
+// TestTokenRotation_Signatures ensures that variadic methods and
+// subscriptions without a ctx are dispatched on rotating connections.
+func TestTokenRotation_Signatures(t *testing.T) {
+	module := &variadicModule{
+		Sum: func(_ context.Context, xs ...int) (int, error) {
+			sum := 0
+			for _, x := range xs {
+				sum += x
+			}
+			return sum, nil
+		},
+		Ticks: func() (<-chan int, error) {
+			ch := make(chan int, 1)
+			ch <- 1
+			close(ch)
+			return ch, nil
+		},
+	}
+	r := &rotatingModule{
+		typ: reflect.TypeOf(module).Elem(),
+		current: &generation{
+			module: reflect.ValueOf(module).Elem(),
+			closer: func() {},
+			done:   make(chan struct{}),
+		},
+	}
+	var rotating variadicModule
+	reflect.ValueOf(&rotating).Elem().Field(0).Set(r.dispatch(0))
+	reflect.ValueOf(&rotating).Elem().Field(1).Set(r.dispatch(1))
+
+	sum, err := rotating.Sum(context.Background(), 1, 2, 3)
+	require.NoError(t, err)
+	require.Equal(t, 6, sum)
+
+	ch, err := rotating.Ticks()
+	require.NoError(t, err)
+	require.Equal(t, 1, <-ch)
+	_, ok := <-ch
+	require.False(t, ok)
+}
@@ -0,0 +1,227 @@
+// Package cache caches the results of calls for data that never changes once
+// finalized: headers by height or hash, blobs by height, namespace and
+// commitment, and extended data squares by data availability header.
+//
+//	c := cache.New(cache.NewMemory(256 << 20))
+//	prometheus.MustRegister(c)
+//	cl, err := client.NewClient(ctx, addr, token, client.WithInterceptors(c.Interceptor()))
+//
+// Concurrent identical calls missing the cache are collapsed into a single
+// call to the node. Failed calls are not cached.
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/sync/singleflight"
+
+	clientbuilder "github.com/celestiaorg/celestia-openrpc/builder"
+	"github.com/celestiaorg/celestia-openrpc/types/header"
+	"github.com/celestiaorg/celestia-openrpc/types/share"
+)
+
+// keys return the cache key of a call of each cached method from its
+// arguments, or false if the call is not cacheable.
+var keys = map[string]func(args []interface{}) (string, bool){
+	"header.GetByHeight": func(args []interface{}) (string, bool) {
+		return fmt.Sprintf("header/height/%d", args[0]), true
+	},
+	"header.GetByHash": func(args []interface{}) (string, bool) {
+		return fmt.Sprintf("header/hash/%x", args[0]), true
+	},
+	"blob.Get": func(args []interface{}) (string, bool) {
+		return fmt.Sprintf("blob/%d/%x/%x", args[0], args[1], args[2]), true
+	},
+	"blob.GetAll": func(args []interface{}) (string, bool) {
+		namespaces, ok := args[1].([]share.Namespace)
+		if !ok {
+			return "", false
+		}
+		encoded := make([]string, len(namespaces))
+		for i, ns := range namespaces {
+			encoded[i] = fmt.Sprintf("%x", []byte(ns))
+		}
+		return fmt.Sprintf("blobs/%d/%s", args[0], strings.Join(encoded, ",")), true
+	},
+	"share.GetEDS": func(args []interface{}) (string, bool) {
+		eh, ok := args[0].(*header.ExtendedHeader)
+		if !ok || eh == nil || eh.DAH == nil {
+			return "", false
+		}
+		return fmt.Sprintf("eds/%x", eh.DAH.Hash()), true
+	},
+}
+
+// Option configures a Cache.
+type Option func(*Cache)
+
+// WithMetricsNamespace prefixes the names of the cache's metrics with
+// namespace.
+func WithMetricsNamespace(namespace string) Option {
+	return func(c *Cache) {
+		c.namespace = namespace
+	}
+}
+
+// Cache is a read-through cache of immutable data in front of the node. It is
+// a prometheus.Collector, to be registered with a registry.
+type Cache struct {
+	store     Store
+	namespace string
+	group     singleflight.Group
+
+	hits      *prometheus.CounterVec
+	misses    *prometheus.CounterVec
+	collapsed *prometheus.CounterVec
+	errors    *prometheus.CounterVec
+
+	entries   *prometheus.Desc
+	bytes     *prometheus.Desc
+	evictions *prometheus.Desc
+}
+
+var _ prometheus.Collector = (*Cache)(nil)
+
+// New returns a cache keeping results in store.
+func New(store Store, opts ...Option) *Cache {
+	c := &Cache{store: store}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	counterOpts := func(name, help string) prometheus.CounterOpts {
+		return prometheus.CounterOpts{
+			Namespace: c.namespace,
+			Subsystem: "celestia_client_cache",
+			Name:      name,
+			Help:      help,
+		}
+	}
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(c.namespace, "celestia_client_cache", name), help, nil, nil)
+	}
+	labels := []string{"module", "method"}
+	c.hits = prometheus.NewCounterVec(counterOpts("hits_total", "Number of calls answered from the cache."), labels)
+	c.misses = prometheus.NewCounterVec(counterOpts("misses_total", "Number of calls that missed the cache."), labels)
+	c.collapsed = prometheus.NewCounterVec(
+		counterOpts("collapsed_total", "Number of calls that missed the cache and waited for an identical call."),
+		labels,
+	)
+	c.errors = prometheus.NewCounterVec(counterOpts("store_errors_total", "Number of failures of the store."), labels)
+	c.entries = desc("entries", "Number of values in the cache.")
+	c.bytes = desc("size_bytes", "Size of the values in the cache.")
+	c.evictions = desc("evictions_total", "Number of values evicted from the cache.")
+	return c
+}
+
+// Describe implements prometheus.Collector.
+func (c *Cache) Describe(ch chan<- *prometheus.Desc) {
+	for _, vec := range []*prometheus.CounterVec{c.hits, c.misses, c.collapsed, c.errors} {
+		vec.Describe(ch)
+	}
+	ch <- c.entries
+	ch <- c.bytes
+	ch <- c.evictions
+}
+
+// Collect implements prometheus.Collector.
+func (c *Cache) Collect(ch chan<- prometheus.Metric) {
+	for _, vec := range []*prometheus.CounterVec{c.hits, c.misses, c.collapsed, c.errors} {
+		vec.Collect(ch)
+	}
+	stats := c.store.Stats()
+	ch <- prometheus.MustNewConstMetric(c.entries, prometheus.GaugeValue, float64(stats.Entries))
+	ch <- prometheus.MustNewConstMetric(c.bytes, prometheus.GaugeValue, float64(stats.Bytes))
+	ch <- prometheus.MustNewConstMetric(c.evictions, prometheus.CounterValue, float64(stats.Evictions))
+}
+
+// Interceptor returns the interceptor answering calls from the cache, to be
+// installed with client.WithInterceptors. Interceptors installed before it
+// see every call, those after it only the calls to the node.
+func (c *Cache) Interceptor() clientbuilder.Interceptor {
+	return func(ctx context.Context, call *clientbuilder.Call, next clientbuilder.Invoker) error {
+		keyOf, ok := keys[call.Method.String()]
+		if !ok {
+			return next(ctx, call)
+		}
+		key, ok := keyOf(call.Args)
+		if !ok {
+			return next(ctx, call)
+		}
+		module, method := call.Method.Namespace, call.Method.Name
+		resultType := call.Method.Type.Out(0)
+
+		value, found, err := c.store.Get(key)
+		if err != nil {
+			c.errors.WithLabelValues(module, method).Inc()
+		}
+		if found {
+			if err := decode(value, resultType, call); err == nil {
+				c.hits.WithLabelValues(module, method).Inc()
+				return nil
+			}
+		}
+		c.misses.WithLabelValues(module, method).Inc()
+
+		var led bool
+		results := c.group.DoChan(key, func() (interface{}, error) {
+			led = true
+			// a call of its own, as the caller may give up before it returns
+			inner := &clientbuilder.Call{Method: call.Method, Args: call.Args}
+			if err := next(ctx, inner); err != nil {
+				return nil, err
+			}
+			value, err := json.Marshal(inner.Result)
+			if err != nil {
+				return nil, err
+			}
+			if err := c.store.Put(key, value); err != nil {
+				c.errors.WithLabelValues(module, method).Inc()
+			}
+			return flight{result: inner.Result, value: value}, nil
+		})
+		select {
+		case res := <-results:
+			if led {
+				if res.Err != nil {
+					return res.Err
+				}
+				call.Result = res.Val.(flight).result
+				return nil
+			}
+			c.collapsed.WithLabelValues(module, method).Inc()
+			if res.Err != nil {
+				if ctx.Err() == nil && (errors.Is(res.Err, context.Canceled) || errors.Is(res.Err, context.DeadlineExceeded)) {
+					// the call waited for was given up on, not this one
+					return next(ctx, call)
+				}
+				return res.Err
+			}
+			return decode(res.Val.(flight).value, resultType, call)
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// flight is the outcome of a call to the node shared by identical calls.
+type flight struct {
+	result interface{}
+	value  []byte
+}
+
+// decode sets the result of call to the value decoded from data.
+func decode(data []byte, typ reflect.Type, call *clientbuilder.Call) error {
+	v := reflect.New(typ)
+	if err := json.Unmarshal(data, v.Interface()); err != nil {
+		return err
+	}
+	call.Result = v.Elem().Interface()
+	return nil
+}
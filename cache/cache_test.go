@@ -0,0 +1,193 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	client "github.com/celestiaorg/celestia-openrpc"
+	clientbuilder "github.com/celestiaorg/celestia-openrpc/builder"
+	"github.com/celestiaorg/celestia-openrpc/testnode"
+	"github.com/celestiaorg/celestia-openrpc/types/blob"
+	"github.com/celestiaorg/celestia-openrpc/types/header"
+	"github.com/celestiaorg/celestia-openrpc/types/share"
+)
+
+func TestCache(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	node, err := testnode.New()
+	require.NoError(t, err)
+	defer node.Close()
+	c := New(NewMemory(64 << 20))
+	cl, err := client.NewClient(ctx, node.URL(), "", client.WithInterceptors(c.Interceptor()))
+	require.NoError(t, err)
+	defer cl.Close()
+
+	ns, err := share.NewBlobNamespaceV0([]byte("cache"))
+	require.NoError(t, err)
+	b, err := blob.NewBlobV0(ns, []byte("hello"))
+	require.NoError(t, err)
+	height, err := cl.Blob.Submit(ctx, []*blob.Blob{b}, blob.DefaultGasPrice())
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		h, err := cl.Header.GetByHeight(ctx, height)
+		require.NoError(t, err)
+		require.Equal(t, node.Head().Hash(), h.Hash())
+		byHash, err := cl.Header.GetByHash(ctx, h.Hash())
+		require.NoError(t, err)
+		require.Equal(t, height, byHash.Height())
+
+		got, err := cl.Blob.Get(ctx, height, ns, b.Commitment)
+		require.NoError(t, err)
+		require.Equal(t, b.Data, got.Data)
+		require.Positive(t, got.Index())
+		all, err := cl.Blob.GetAll(ctx, height, []share.Namespace{ns})
+		require.NoError(t, err)
+		require.Len(t, all, 1)
+
+		eds, err := cl.Share.GetEDS(ctx, h)
+		require.NoError(t, err)
+		require.Len(t, h.DAH.RowRoots, int(eds.Width()))
+	}
+	for _, method := range []string{"GetByHeight", "GetByHash"} {
+		require.EqualValues(t, 1, testutil.ToFloat64(c.hits.WithLabelValues("header", method)), method)
+		require.EqualValues(t, 1, testutil.ToFloat64(c.misses.WithLabelValues("header", method)), method)
+	}
+	for _, method := range []string{"Get", "GetAll"} {
+		require.EqualValues(t, 1, testutil.ToFloat64(c.hits.WithLabelValues("blob", method)), method)
+	}
+	require.EqualValues(t, 1, testutil.ToFloat64(c.hits.WithLabelValues("share", "GetEDS")))
+	require.Equal(t, 5, c.store.Stats().Entries)
+
+	// failures are not cached
+	_, err = cl.Header.GetByHeight(ctx, height+1)
+	require.Error(t, err)
+	_, err = node.ProduceBlock()
+	require.NoError(t, err)
+	_, err = cl.Header.GetByHeight(ctx, height+1)
+	require.NoError(t, err)
+}
+
+type headerModule struct {
+	GetByHeight func(context.Context, uint64) (*header.ExtendedHeader, error) `perm:"read"`
+}
+
+type testClient struct {
+	Header headerModule
+}
+
+func TestCache_Collapse(t *testing.T) {
+	c := New(NewMemory(1 << 20))
+	var calls atomic.Int32
+	release := make(chan struct{})
+	cl := &testClient{Header: headerModule{
+		GetByHeight: func(_ context.Context, height uint64) (*header.ExtendedHeader, error) {
+			calls.Add(1)
+			<-release
+			return &header.ExtendedHeader{}, nil
+		},
+	}}
+	dialer, err := clientbuilder.NewDialer(
+		context.Background(),
+		"http://localhost:26658",
+		"",
+		clientbuilder.WithInterceptors(c.Interceptor()),
+	)
+	require.NoError(t, err)
+	require.NoError(t, dialer.Wrap(cl))
+
+	const callers = 5
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := cl.Header.GetByHeight(context.Background(), 1)
+			require.NoError(t, err)
+		}()
+	}
+	require.Eventually(t, func() bool {
+		return testutil.ToFloat64(c.misses.WithLabelValues("header", "GetByHeight")) == callers
+	}, time.Second, time.Millisecond)
+	// let the last caller get from counting its miss to waiting for the call
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	require.EqualValues(t, 1, calls.Load())
+	require.EqualValues(t, callers-1, testutil.ToFloat64(c.collapsed.WithLabelValues("header", "GetByHeight")))
+}
+
+func TestDisk(t *testing.T) {
+	dir := t.TempDir()
+	d, err := NewDisk(dir, 10)
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, d.Put(fmt.Sprint(i), []byte("abcd")))
+	}
+	// too large to be stored at all
+	require.NoError(t, d.Put("large", make([]byte, 11)))
+	require.Equal(t, Stats{Entries: 2, Bytes: 8, Evictions: 1}, d.Stats())
+
+	_, found, err := d.Get("0")
+	require.NoError(t, err)
+	require.False(t, found)
+	value, found, err := d.Get("1")
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, []byte("abcd"), value)
+
+	// survives reopening, with "1" used more recently than "2"
+	d, err = NewDisk(dir, 10)
+	require.NoError(t, err)
+	require.Equal(t, 2, d.Stats().Entries)
+	require.NoError(t, d.Put("3", []byte("abcd")))
+	_, found, err = d.Get("2")
+	require.NoError(t, err)
+	require.False(t, found)
+	_, found, err = d.Get("1")
+	require.NoError(t, err)
+	require.True(t, found)
+}
+
+// TestDisk_ForeignFiles ensures that files the store did not write are
+// neither counted nor evicted.
+func TestDisk_ForeignFiles(t *testing.T) {
+	dir := t.TempDir()
+	foreign := []string{"notes.txt", ".tmp-notes", strings.Repeat("A", 64)}
+	for _, name := range foreign {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("abcdefgh"), 0o644))
+	}
+	// left over by an interrupted write
+	require.NoError(t, os.WriteFile(filepath.Join(dir, tempPrefix+"123"), []byte("abcd"), 0o644))
+
+	d, err := NewDisk(dir, 10)
+	require.NoError(t, err)
+	require.Equal(t, Stats{}, d.Stats())
+	for i := 0; i < 3; i++ {
+		require.NoError(t, d.Put(fmt.Sprint(i), []byte("abcd")))
+	}
+	require.Equal(t, Stats{Entries: 2, Bytes: 8, Evictions: 1}, d.Stats())
+
+	d, err = NewDisk(dir, 4)
+	require.NoError(t, err)
+	require.Equal(t, 1, d.Stats().Entries)
+
+	for _, name := range foreign {
+		require.FileExists(t, filepath.Join(dir, name))
+	}
+	require.NoFileExists(t, filepath.Join(dir, tempPrefix+"123"))
+}
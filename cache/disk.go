@@ -0,0 +1,172 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// tempPrefix prefixes the files being written, which are left over only if
+// writing was interrupted.
+const tempPrefix = ".tmp-"
+
+// Disk is a Store holding values in files of a directory, evicting the least
+// recently used ones beyond its size limit. The values in the directory are
+// picked up again when it is reopened, while files it did not write are left
+// alone.
+type Disk struct {
+	dir string
+
+	mu  sync.Mutex
+	lru *lru
+}
+
+var _ Store = (*Disk)(nil)
+
+// NewDisk returns a store holding up to maxBytes of values in dir, which is
+// created if missing.
+func NewDisk(dir string, maxBytes int64) (*Disk, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("cache: creating directory: %w", err)
+	}
+	d := &Disk{dir: dir}
+	d.lru = newLRU(maxBytes, func(name string) {
+		_ = os.Remove(filepath.Join(dir, name))
+	})
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("cache: reading directory: %w", err)
+	}
+	type file struct {
+		name    string
+		size    int64
+		modTime time.Time
+	}
+	var files []file
+	for _, e := range entries {
+		if isTempFile(e.Name()) {
+			_ = os.Remove(filepath.Join(dir, e.Name()))
+			continue
+		}
+		if !e.Type().IsRegular() || !isValueFile(e.Name()) {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, file{name: e.Name(), size: info.Size(), modTime: info.ModTime()})
+	}
+	// the modification time is bumped on reads, so the newest is the most
+	// recently used
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].modTime.After(files[j].modTime)
+	})
+	for _, f := range files {
+		d.lru.addOldest(f.name, f.size)
+	}
+	for d.lru.bytes > d.lru.maxBytes {
+		d.lru.evict(d.lru.order.Back())
+	}
+	return d, nil
+}
+
+func fileName(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// isValueFile reports whether name is one given by fileName.
+func isValueFile(name string) bool {
+	if len(name) != 2*sha256.Size {
+		return false
+	}
+	for _, c := range name {
+		if !('0' <= c && c <= '9' || 'a' <= c && c <= 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// isTempFile reports whether name is one of a file being written, to which
+// os.CreateTemp appends digits to tempPrefix.
+func isTempFile(name string) bool {
+	digits, ok := strings.CutPrefix(name, tempPrefix)
+	if !ok || digits == "" {
+		return false
+	}
+	for _, c := range digits {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Get implements Store.
+func (d *Disk) Get(key string) ([]byte, bool, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	name := fileName(key)
+	if _, ok := d.lru.get(name); !ok {
+		return nil, false, nil
+	}
+	path := filepath.Join(d.dir, name)
+	value, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		// removed behind our back
+		d.lru.evict(d.lru.entries[name])
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	now := time.Now()
+	_ = os.Chtimes(path, now, now)
+	return value, true, nil
+}
+
+// Put implements Store.
+func (d *Disk) Put(key string, value []byte) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if int64(len(value)) > d.lru.maxBytes {
+		return nil
+	}
+
+	tmp, err := os.CreateTemp(d.dir, tempPrefix+"*")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(value)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	name := fileName(key)
+	if err == nil {
+		err = os.Rename(tmp.Name(), filepath.Join(d.dir, name))
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	d.lru.add(name, int64(len(value)), nil)
+	return nil
+}
+
+// Stats implements Store.
+func (d *Disk) Stats() Stats {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.lru.stats()
+}
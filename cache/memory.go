@@ -0,0 +1,43 @@
+package cache
+
+import "sync"
+
+// Memory is a Store holding values in memory, evicting the least recently used
+// ones beyond its size limit.
+type Memory struct {
+	mu  sync.Mutex
+	lru *lru
+}
+
+var _ Store = (*Memory)(nil)
+
+// NewMemory returns a store holding up to maxBytes of values.
+func NewMemory(maxBytes int64) *Memory {
+	return &Memory{lru: newLRU(maxBytes, nil)}
+}
+
+// Get implements Store.
+func (m *Memory) Get(key string) ([]byte, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.lru.get(key)
+	if !ok {
+		return nil, false, nil
+	}
+	return e.value, true, nil
+}
+
+// Put implements Store.
+func (m *Memory) Put(key string, value []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.lru.add(key, int64(len(value)), value)
+	return nil
+}
+
+// Stats implements Store.
+func (m *Memory) Stats() Stats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.lru.stats()
+}
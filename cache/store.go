@@ -0,0 +1,103 @@
+package cache
+
+import (
+	"container/list"
+)
+
+// Store holds encoded results by key.
+type Store interface {
+	// Get returns the value stored under key, if any.
+	Get(key string) ([]byte, bool, error)
+	// Put stores value under key, evicting other values to stay within the
+	// store's limits. Values larger than the limit are not stored.
+	Put(key string, value []byte) error
+	// Stats reports the content of the store.
+	Stats() Stats
+}
+
+// Stats is the content of a store.
+type Stats struct {
+	Entries int
+	Bytes   int64
+	// Evictions is the number of values evicted so far.
+	Evictions uint64
+}
+
+// lru tracks the size of entries in least recently used order, evicting the
+// oldest beyond maxBytes. It is not safe for concurrent use.
+type lru struct {
+	maxBytes int64
+	bytes    int64
+	order    *list.List
+	entries  map[string]*list.Element
+	evicted  uint64
+	// onEvict is called with the key of each evicted entry.
+	onEvict func(key string)
+}
+
+type lruEntry struct {
+	key   string
+	size  int64
+	value []byte
+}
+
+func newLRU(maxBytes int64, onEvict func(string)) *lru {
+	return &lru{
+		maxBytes: maxBytes,
+		order:    list.New(),
+		entries:  map[string]*list.Element{},
+		onEvict:  onEvict,
+	}
+}
+
+// get returns the entry under key, marking it as recently used.
+func (l *lru) get(key string) (*lruEntry, bool) {
+	el, ok := l.entries[key]
+	if !ok {
+		return nil, false
+	}
+	l.order.MoveToFront(el)
+	return el.Value.(*lruEntry), true
+}
+
+// add adds or replaces the entry under key as the most recently used one. It
+// reports false if the entry does not fit at all.
+func (l *lru) add(key string, size int64, value []byte) bool {
+	if size > l.maxBytes {
+		return false
+	}
+	if el, ok := l.entries[key]; ok {
+		e := el.Value.(*lruEntry)
+		l.bytes += size - e.size
+		e.size, e.value = size, value
+		l.order.MoveToFront(el)
+	} else {
+		l.entries[key] = l.order.PushFront(&lruEntry{key: key, size: size, value: value})
+		l.bytes += size
+	}
+	for l.bytes > l.maxBytes {
+		l.evict(l.order.Back())
+	}
+	return true
+}
+
+// addOldest adds an entry as the least recently used one, for loading
+// entries oldest last.
+func (l *lru) addOldest(key string, size int64) {
+	l.entries[key] = l.order.PushBack(&lruEntry{key: key, size: size})
+	l.bytes += size
+}
+
+func (l *lru) evict(el *list.Element) {
+	e := l.order.Remove(el).(*lruEntry)
+	delete(l.entries, e.key)
+	l.bytes -= e.size
+	l.evicted++
+	if l.onEvict != nil {
+		l.onEvict(e.key)
+	}
+}
+
+func (l *lru) stats() Stats {
+	return Stats{Entries: len(l.entries), Bytes: l.bytes, Evictions: l.evicted}
+}
@@ -2,10 +2,9 @@ package client
 
 import (
 	"context"
-	"fmt"
-	"net/http"
 
 	clientbuilder "github.com/celestiaorg/celestia-openrpc/builder"
+	"github.com/celestiaorg/celestia-openrpc/openrpc"
 	"github.com/celestiaorg/celestia-openrpc/types/blob"
 	"github.com/celestiaorg/celestia-openrpc/types/da"
 	"github.com/celestiaorg/celestia-openrpc/types/das"
@@ -15,11 +14,9 @@ import (
 	"github.com/celestiaorg/celestia-openrpc/types/p2p"
 	"github.com/celestiaorg/celestia-openrpc/types/share"
 	"github.com/celestiaorg/celestia-openrpc/types/state"
-
-	"github.com/filecoin-project/go-jsonrpc"
 )
 
-const AuthKey = "Authorization"
+const AuthKey = clientbuilder.AuthKey
 
 type Client struct {
 	Fraud  fraud.API
@@ -33,6 +30,12 @@ type Client struct {
 	DA     da.API
 
 	closer clientbuilder.MultiClientCloser
+	// perms are the permissions of the token, nil if unknown.
+	perms clientbuilder.Permissions
+	// dialer sends batch requests, nil if the client spans several nodes.
+	dialer *clientbuilder.Dialer
+	// discover fetches the OpenRPC document of the node.
+	discover func(ctx context.Context) (*openrpc.Document, error)
 }
 
 // Close closes the connections to all namespaces registered on the client.
@@ -40,33 +43,47 @@ func (c *Client) Close() {
 	c.closer.CloseAll()
 }
 
-func NewClient(ctx context.Context, addr string, token string) (*Client, error) {
-	var authHeader http.Header
-	if token != "" {
-		authHeader = http.Header{AuthKey: []string{fmt.Sprintf("Bearer %s", token)}}
-	}
-
+// NewClient connects to the node served at addr, an http(s), ws(s) or
+// unix:///path/to/socket address, sharing a single connection between all
+// namespaces. The token, if not empty, is sent as a bearer token with every
+// request, unless a TokenProvider is configured with WithTokenProvider.
+func NewClient(ctx context.Context, addr string, token string, opts ...Option) (*Client, error) {
 	var client Client
 
-	modules := map[string]interface{}{
-		"fraud":  &client.Fraud,
-		"blob":   &client.Blob,
-		"header": &client.Header,
-		"state":  &client.State,
-		"share":  &client.Share,
-		"das":    &client.DAS,
-		"p2p":    &client.P2P,
-		"node":   &client.Node,
-		"da":     &client.DA,
+	// known errors are decoded unless the options say otherwise
+	opts = append([]Option{clientbuilder.WithErrors(Errors())}, opts...)
+	dialer, err := clientbuilder.NewDialer(ctx, addr, token, opts...)
+	if err != nil {
+		return nil, err
+	}
+	client.closer.Register(dialer.Close)
+	client.dialer = dialer
+	client.discover = func(ctx context.Context) (*openrpc.Document, error) {
+		return discover(ctx, dialer)
 	}
 
-	for name, module := range modules {
-		closer, err := jsonrpc.NewClient(ctx, addr, name, module, authHeader)
-		if err != nil {
+	closer, err := dialer.DialAll(ctx, &client)
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+	client.closer.Register(closer)
+
+	client.perms = dialer.Permissions(ctx, client.Node.AuthVerify)
+	if client.perms != nil {
+		if err := clientbuilder.CheckPermissions(&client, client.perms); err != nil {
+			client.Close()
 			return nil, err
 		}
-		client.closer.Register(closer)
 	}
-
+	// installed last, so that denied calls go through the middleware too
+	if err := dialer.Wrap(&client); err != nil {
+		client.Close()
+		return nil, err
+	}
+	if err := dialer.RunChecks(ctx, &client); err != nil {
+		client.Close()
+		return nil, err
+	}
 	return &client, nil
 }
@@ -0,0 +1,92 @@
+// Command openrpc-doc writes the OpenRPC document describing the API spoken by
+// client.Client, so that clients in other languages can be generated from it:
+//
+//	go run ./cmd/openrpc-doc -out openrpc.json
+//
+// The documentation of the methods is taken from the API structs in -types.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+
+	client "github.com/celestiaorg/celestia-openrpc"
+	"github.com/celestiaorg/celestia-openrpc/openrpc"
+)
+
+func main() {
+	out := flag.String("out", "", "file to write the document to, stdout if empty")
+	types := flag.String("types", "types", "directory holding the modules, for their documentation")
+	version := flag.String("version", "", "version of the API")
+	flag.Parse()
+
+	if err := run(*out, *types, *version); err != nil {
+		fmt.Fprintln(os.Stderr, "openrpc-doc:", err)
+		os.Exit(1)
+	}
+}
+
+func run(out, types, version string) error {
+	docs, err := parseDocs(types)
+	if err != nil {
+		return err
+	}
+	doc, err := openrpc.Emit(&client.Client{}, openrpc.EmitConfig{
+		Info: openrpc.Info{Title: "Celestia Node API", Version: version},
+		Docs: docs,
+	})
+	if err != nil {
+		return err
+	}
+
+	raw, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return err
+	}
+	raw = append(raw, '\n')
+	if out == "" {
+		_, err = os.Stdout.Write(raw)
+		return err
+	}
+	return os.WriteFile(out, raw, 0o644) //nolint:gosec
+}
+
+// parseDocs collects the doc comments of the fields of the API struct in every
+// <dir>/<module>/api.go, keyed by "<module>.<Method>".
+func parseDocs(dir string) (map[string]string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*", "api.go"))
+	if err != nil {
+		return nil, err
+	}
+
+	docs := make(map[string]string)
+	for _, path := range files {
+		file, err := parser.ParseFile(token.NewFileSet(), path, nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		ast.Inspect(file, func(n ast.Node) bool {
+			spec, ok := n.(*ast.TypeSpec)
+			if !ok || spec.Name.Name != "API" {
+				return true
+			}
+			for _, field := range spec.Type.(*ast.StructType).Fields.List {
+				if field.Doc == nil {
+					continue
+				}
+				for _, name := range field.Names {
+					docs[file.Name.Name+"."+name.Name] = strings.TrimSpace(field.Doc.Text())
+				}
+			}
+			return false
+		})
+	}
+	return docs, nil
+}
@@ -0,0 +1,413 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"path"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/celestiaorg/celestia-openrpc/openrpc"
+)
+
+const repoPath = "github.com/celestiaorg/celestia-openrpc"
+
+// packages maps the packages a type may be qualified with to their import
+// path. The modules of the API live in types/<module>.
+var packages = map[string]string{
+	"context": "context",
+	"json":    "encoding/json",
+	"auth":    "github.com/filecoin-project/go-jsonrpc/auth",
+	"libhead": "github.com/celestiaorg/go-header",
+	"sync":    "github.com/celestiaorg/go-header/sync",
+	"peer":    "github.com/libp2p/go-libp2p/core/peer",
+
+	"blob":   repoPath + "/types/blob",
+	"da":     repoPath + "/types/da",
+	"das":    repoPath + "/types/das",
+	"fraud":  repoPath + "/types/fraud",
+	"header": repoPath + "/types/header",
+	"node":   repoPath + "/types/node",
+	"p2p":    repoPath + "/types/p2p",
+	"share":  repoPath + "/types/share",
+	"state":  repoPath + "/types/state",
+}
+
+var builtins = map[string]bool{
+	"bool": true, "byte": true, "string": true, "error": true,
+	"int": true, "int8": true, "int16": true, "int32": true, "int64": true,
+	"uint": true, "uint8": true, "uint16": true, "uint32": true, "uint64": true,
+	"float32": true, "float64": true,
+}
+
+// Generate returns the source of the API struct of every module described by
+// doc, keyed by "<module>/api.go". Only the listed modules are generated, or
+// all if none are listed.
+func Generate(doc *openrpc.Document, modules ...string) (map[string][]byte, error) {
+	wanted := make(map[string]bool, len(modules))
+	for _, m := range modules {
+		wanted[m] = true
+	}
+
+	var order []string
+	byModule := make(map[string][]openrpc.Method)
+	for _, m := range doc.Methods {
+		module, _, err := m.Module()
+		if err != nil {
+			return nil, err
+		}
+		if len(wanted) > 0 && !wanted[module] {
+			continue
+		}
+		if _, ok := byModule[module]; !ok {
+			order = append(order, module)
+		}
+		byModule[module] = append(byModule[module], m)
+	}
+
+	files := make(map[string][]byte, len(order))
+	for _, module := range order {
+		g := &generator{
+			module:     module,
+			components: doc.Components,
+			imports:    map[string]string{},
+			defined:    map[string]bool{},
+		}
+		src, err := g.file(byModule[module])
+		if err != nil {
+			return nil, fmt.Errorf("generating module %s: %w", module, err)
+		}
+		files[module+"/api.go"] = src
+	}
+	return files, nil
+}
+
+// generator generates the file of a single module.
+type generator struct {
+	module     string
+	components *openrpc.Components
+
+	imports map[string]string
+	// types are the definitions of the types generated from component schemas.
+	types   bytes.Buffer
+	defined map[string]bool
+	pending []string
+}
+
+func (g *generator) file(methods []openrpc.Method) ([]byte, error) {
+	var api bytes.Buffer
+	api.WriteString("type API struct {\n")
+	for _, m := range methods {
+		if err := g.method(&api, m); err != nil {
+			return nil, err
+		}
+	}
+	api.WriteString("}\n")
+
+	for len(g.pending) > 0 {
+		name := g.pending[0]
+		g.pending = g.pending[1:]
+		if err := g.define(name); err != nil {
+			return nil, err
+		}
+	}
+
+	var src bytes.Buffer
+	src.WriteString("// Code generated by openrpc-gen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&src, "package %s\n\n", g.module)
+	src.WriteString(g.importDecl())
+	src.Write(api.Bytes())
+	if g.types.Len() > 0 {
+		src.WriteString("\n")
+		src.Write(g.types.Bytes())
+	}
+
+	out, err := format.Source(src.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %w\n%s", err, src.Bytes())
+	}
+	return out, nil
+}
+
+// importDecl returns the import declaration, grouping the standard library,
+// other modules and this repository as goimports would.
+func (g *generator) importDecl() string {
+	if len(g.imports) == 0 {
+		return ""
+	}
+	groups := make([][]string, 3)
+	for alias, p := range g.imports {
+		spec := fmt.Sprintf("%q", p)
+		if path.Base(p) != alias {
+			spec = alias + " " + spec
+		}
+		group := 0
+		switch {
+		case strings.HasPrefix(p, repoPath):
+			group = 2
+		case strings.Contains(strings.Split(p, "/")[0], "."):
+			group = 1
+		}
+		groups[group] = append(groups[group], spec)
+	}
+
+	var decl strings.Builder
+	decl.WriteString("import (\n")
+	sep := ""
+	for _, group := range groups {
+		if len(group) == 0 {
+			continue
+		}
+		sort.Slice(group, func(i, j int) bool {
+			return importPath(group[i]) < importPath(group[j])
+		})
+		decl.WriteString(sep + strings.Join(group, "\n") + "\n")
+		sep = "\n"
+	}
+	decl.WriteString(")\n\n")
+	return decl.String()
+}
+
+func importPath(spec string) string {
+	return spec[strings.Index(spec, `"`):]
+}
+
+func (g *generator) method(w *bytes.Buffer, m openrpc.Method) error {
+	_, name, err := m.Module()
+	if err != nil {
+		return err
+	}
+	if !token.IsExported(name) {
+		return fmt.Errorf("method %s is not exported", m.Name)
+	}
+	perm := m.Permission()
+	if perm == "" {
+		return fmt.Errorf("method %s declares no permission", m.Name)
+	}
+
+	params := []string{"ctx " + g.qualify("context.Context")}
+	names := map[string]int{"ctx": 1}
+	for i, p := range m.Params {
+		typ, err := g.typeOf(p)
+		if err != nil {
+			return fmt.Errorf("param %d of %s: %w", i, m.Name, err)
+		}
+		params = append(params, uniqueName(names, paramName(p, i))+" "+typ)
+	}
+
+	results := "error"
+	if m.Result != nil {
+		typ, err := g.typeOf(*m.Result)
+		if err != nil {
+			return fmt.Errorf("result of %s: %w", m.Name, err)
+		}
+		results = "(" + typ + ", error)"
+	}
+
+	if doc := m.Doc(); doc != "" {
+		for _, line := range strings.Split(doc, "\n") {
+			fmt.Fprintf(w, "// %s\n", line)
+		}
+	}
+	if m.Deprecated {
+		w.WriteString("//\n// Deprecated: the method is deprecated by the node.\n")
+	}
+	fmt.Fprintf(w, "%s func(%s) %s `perm:%q`\n", name, strings.Join(params, ", "), results, perm)
+	return nil
+}
+
+// typeOf returns the Go type of a parameter or result. The Go type stated by
+// the document is used if it maps to a known type, falling back to a type
+// derived from the schema.
+func (g *generator) typeOf(c openrpc.ContentDescriptor) (string, error) {
+	if expr := c.Type(); expr != "" {
+		if typ, ok := g.resolve(expr); ok {
+			return typ, nil
+		}
+	}
+	return g.schemaType(c.Schema)
+}
+
+// resolve maps a Go type expression, e.g. "[]*blob.Blob", to the type as used
+// within the generated package.
+func (g *generator) resolve(expr string) (string, bool) {
+	for _, prefix := range []string{"*", "[]", "<-chan "} {
+		if strings.HasPrefix(expr, prefix) {
+			inner, ok := g.resolve(strings.TrimPrefix(expr, prefix))
+			return prefix + inner, ok
+		}
+	}
+	if builtins[expr] {
+		return expr, true
+	}
+	pkg, name, ok := strings.Cut(expr, ".")
+	if !ok || !token.IsExported(name) {
+		return "", false
+	}
+	if pkg == g.module {
+		return name, true
+	}
+	if _, ok := packages[pkg]; !ok {
+		return "", false
+	}
+	return g.qualify(expr), true
+}
+
+// qualify records the import of the package of a qualified type.
+func (g *generator) qualify(expr string) string {
+	pkg, _, _ := strings.Cut(expr, ".")
+	g.imports[pkg] = packages[pkg]
+	return expr
+}
+
+func (g *generator) schemaType(s *openrpc.Schema) (string, error) {
+	if s == nil {
+		return g.qualify("json.RawMessage"), nil
+	}
+	if name := s.RefName(); name != "" {
+		if g.components == nil || g.components.Schemas[name] == nil {
+			return "", fmt.Errorf("unknown schema %q", s.Ref)
+		}
+		typ := exportedName(name)
+		if !g.defined[typ] {
+			g.defined[typ] = true
+			g.pending = append(g.pending, name)
+		}
+		return typ, nil
+	}
+
+	switch {
+	case s.Type.Is("integer"):
+		switch s.Format {
+		case "int", "int8", "int16", "int32", "int64",
+			"uint", "uint8", "uint16", "uint32", "uint64":
+			return s.Format, nil
+		}
+		return "int64", nil
+	case s.Type.Is("number"):
+		return "float64", nil
+	case s.Type.Is("boolean"):
+		return "bool", nil
+	case s.Type.Is("string"):
+		if s.ContentEncoding == "base64" {
+			return "[]byte", nil
+		}
+		return "string", nil
+	case s.Type.Is("array"):
+		elem, err := g.schemaType(s.Items)
+		if err != nil {
+			return "", err
+		}
+		return "[]" + elem, nil
+	}
+	return g.qualify("json.RawMessage"), nil
+}
+
+// define generates a struct for a component schema.
+func (g *generator) define(name string) error {
+	s := g.components.Schemas[name]
+	typ := exportedName(name)
+
+	required := make(map[string]bool, len(s.Required))
+	for _, r := range s.Required {
+		required[r] = true
+	}
+	props := make([]string, 0, len(s.Properties))
+	for prop := range s.Properties {
+		props = append(props, prop)
+	}
+	sort.Strings(props)
+
+	if s.Description != "" {
+		fmt.Fprintf(&g.types, "// %s\n", s.Description)
+	}
+	fmt.Fprintf(&g.types, "type %s struct {\n", typ)
+	for _, prop := range props {
+		fieldType, err := g.schemaType(s.Properties[prop])
+		if err != nil {
+			return fmt.Errorf("property %s of %s: %w", prop, name, err)
+		}
+		if s.Properties[prop].RefName() != "" && !required[prop] {
+			fieldType = "*" + fieldType
+		}
+		tag := prop
+		if !required[prop] {
+			tag += ",omitempty"
+		}
+		fmt.Fprintf(&g.types, "%s %s `json:%q`\n", exportedName(prop), fieldType, tag)
+	}
+	g.types.WriteString("}\n\n")
+	return nil
+}
+
+// paramName returns the name of the i-th parameter. celestia-node names
+// parameters by their type, in which case a name is derived from it.
+func paramName(p openrpc.ContentDescriptor, i int) string {
+	name := p.Name
+	if p.GoType == "" && p.Type() != "" {
+		name = strings.TrimLeft(strings.ReplaceAll(p.Name, "<-chan ", ""), "*[]")
+		if _, typ, ok := strings.Cut(name, "."); ok {
+			name = typ
+			if strings.Contains(p.Name, "[]") {
+				name += "s"
+			}
+		} else {
+			name = ""
+		}
+	}
+	name = identifier(name)
+	if name == "" || builtins[name] || token.IsKeyword(name) {
+		return fmt.Sprintf("arg%d", i)
+	}
+	return name
+}
+
+func uniqueName(names map[string]int, name string) string {
+	names[name]++
+	if n := names[name]; n > 1 {
+		return fmt.Sprintf("%s%d", name, n)
+	}
+	return name
+}
+
+// identifier turns a name such as "share_version" or "Namespace" into a lower
+// camel case identifier.
+func identifier(name string) string {
+	return lowerFirst(camel(name))
+}
+
+func exportedName(name string) string {
+	name = camel(name)
+	if name == "" {
+		return name
+	}
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+func camel(name string) string {
+	var b strings.Builder
+	upper := false
+	for _, r := range name {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) && b.Len() > 0:
+			if upper {
+				r = unicode.ToUpper(r)
+			}
+			b.WriteRune(r)
+			upper = false
+		default:
+			upper = b.Len() > 0
+		}
+	}
+	return b.String()
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	client "github.com/celestiaorg/celestia-openrpc"
+	"github.com/celestiaorg/celestia-openrpc/openrpc"
+	"github.com/celestiaorg/celestia-openrpc/types/blob"
+	"github.com/celestiaorg/celestia-openrpc/types/header"
+	"github.com/celestiaorg/celestia-openrpc/types/node"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+func readSpec(t *testing.T) *openrpc.Document {
+	f, err := os.Open("testdata/openrpc.json")
+	require.NoError(t, err)
+	defer f.Close()
+	doc, err := openrpc.Read(f)
+	require.NoError(t, err)
+	return doc
+}
+
+func TestGenerate(t *testing.T) {
+	files, err := Generate(readSpec(t))
+	require.NoError(t, err)
+	require.Len(t, files, 4)
+
+	for name, src := range files {
+		golden := filepath.Join("testdata", "golden", filepath.FromSlash(name)+".golden")
+		if *update {
+			require.NoError(t, os.MkdirAll(filepath.Dir(golden), 0o755))
+			require.NoError(t, os.WriteFile(golden, src, 0o600))
+			continue
+		}
+		want, err := os.ReadFile(golden)
+		require.NoError(t, err)
+		require.Equal(t, string(want), string(src), name)
+	}
+}
+
+func TestGenerate_Modules(t *testing.T) {
+	files, err := Generate(readSpec(t), "node")
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	require.Contains(t, files, "node/api.go")
+}
+
+// TestGenerate_MatchesTypes ensures that the code generated for the modules
+// of the fixture matches the hand-written API structs.
+func TestGenerate_MatchesTypes(t *testing.T) {
+	files, err := Generate(readSpec(t), "blob", "header", "node")
+	require.NoError(t, err)
+
+	apis := map[string]reflect.Type{
+		"blob":   reflect.TypeOf(blob.API{}),
+		"header": reflect.TypeOf(header.API{}),
+		"node":   reflect.TypeOf(node.API{}),
+	}
+	for module, typ := range apis {
+		fields := parseAPI(t, module, files[module+"/api.go"])
+		require.NotEmpty(t, fields)
+		for name, got := range fields {
+			field, ok := typ.FieldByName(name)
+			require.True(t, ok, "%s.%s", module, name)
+			require.Equal(t, field.Tag.Get("perm"), got.perm, "%s.%s", module, name)
+			require.Equal(t, field.Type.String(), got.typ, "%s.%s", module, name)
+		}
+	}
+}
+
+// TestGenerate_RoundTrip ensures that the document emitted for the Client
+// generates its API structs again.
+func TestGenerate_RoundTrip(t *testing.T) {
+	doc, err := openrpc.Emit(&client.Client{}, openrpc.EmitConfig{})
+	require.NoError(t, err)
+	files, err := Generate(doc, "blob", "header", "node")
+	require.NoError(t, err)
+
+	apis := map[string]reflect.Type{
+		"blob":   reflect.TypeOf(blob.API{}),
+		"header": reflect.TypeOf(header.API{}),
+		"node":   reflect.TypeOf(node.API{}),
+	}
+	for module, typ := range apis {
+		fields := parseAPI(t, module, files[module+"/api.go"])
+		require.Len(t, fields, typ.NumField(), module)
+		for name, got := range fields {
+			field, ok := typ.FieldByName(name)
+			require.True(t, ok, "%s.%s", module, name)
+			require.Equal(t, field.Tag.Get("perm"), got.perm, "%s.%s", module, name)
+			require.Equal(t, field.Type.String(), got.typ, "%s.%s", module, name)
+		}
+	}
+}
+
+// integers has a field of every integer kind.
+type integers struct {
+	Int    int
+	Int8   int8
+	Int16  int16
+	Int32  int32
+	Int64  int64
+	Uint   uint
+	Uint8  uint8
+	Uint16 uint16
+	Uint32 uint32
+	Uint64 uint64
+}
+
+// TestGenerate_Integers ensures that integers keep their kind when emitted
+// and generated again.
+func TestGenerate_Integers(t *testing.T) {
+	var c struct {
+		Ints struct {
+			Get func(context.Context) (*integers, error) `perm:"read"`
+		}
+	}
+	doc, err := openrpc.Emit(&c, openrpc.EmitConfig{})
+	require.NoError(t, err)
+	files, err := Generate(doc)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+
+	golden := filepath.Join("testdata", "golden", "ints", "api.go.golden")
+	if *update {
+		require.NoError(t, os.MkdirAll(filepath.Dir(golden), 0o755))
+		require.NoError(t, os.WriteFile(golden, files["ints/api.go"], 0o600))
+		return
+	}
+	want, err := os.ReadFile(golden)
+	require.NoError(t, err)
+	require.Equal(t, string(want), string(files["ints/api.go"]))
+}
+
+type apiField struct {
+	typ  string
+	perm string
+}
+
+// parseAPI returns the fields of the generated API struct, their types
+// written as reflect would.
+func parseAPI(t *testing.T, module string, src []byte) map[string]apiField {
+	file, err := parser.ParseFile(token.NewFileSet(), module+".go", src, 0)
+	require.NoError(t, err)
+
+	// reflect names packages by their name, not their import alias
+	names := map[string]string{"libhead": "header"}
+	var typeString func(expr ast.Expr) string
+	typeString = func(expr ast.Expr) string {
+		switch e := expr.(type) {
+		case *ast.Ident:
+			if ast.IsExported(e.Name) {
+				return module + "." + e.Name
+			}
+			return e.Name
+		case *ast.SelectorExpr:
+			pkg := e.X.(*ast.Ident).Name
+			if name, ok := names[pkg]; ok {
+				pkg = name
+			}
+			return pkg + "." + e.Sel.Name
+		case *ast.StarExpr:
+			return "*" + typeString(e.X)
+		case *ast.ArrayType:
+			return "[]" + typeString(e.Elt)
+		case *ast.ChanType:
+			return "<-chan " + typeString(e.Value)
+		case *ast.FuncType:
+			var params, results []string
+			for _, p := range e.Params.List {
+				for range p.Names {
+					params = append(params, typeString(p.Type))
+				}
+			}
+			for _, r := range e.Results.List {
+				results = append(results, typeString(r.Type))
+			}
+			s := "func(" + strings.Join(params, ", ") + ")"
+			if len(results) == 1 {
+				return s + " " + results[0]
+			}
+			return s + " (" + strings.Join(results, ", ") + ")"
+		}
+		t.Fatalf("unexpected expression %T", expr)
+		return ""
+	}
+
+	fields := make(map[string]apiField)
+	ast.Inspect(file, func(n ast.Node) bool {
+		spec, ok := n.(*ast.TypeSpec)
+		if !ok || spec.Name.Name != "API" {
+			return true
+		}
+		for _, f := range spec.Type.(*ast.StructType).Fields.List {
+			tag := reflect.StructTag(strings.Trim(f.Tag.Value, "`"))
+			fields[f.Names[0].Name] = apiField{typ: typeString(f.Type), perm: tag.Get("perm")}
+		}
+		return false
+	})
+	return fields
+}
@@ -0,0 +1,72 @@
+// Command openrpc-gen generates the API structs of the modules described by an
+// OpenRPC document, such as the one published by celestia-node:
+//
+//	go run ./cmd/openrpc-gen -spec openrpc.json -out types -modules blob,header
+//
+// Each module is written to <out>/<module>/api.go.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/celestiaorg/celestia-openrpc/openrpc"
+)
+
+func main() {
+	spec := flag.String("spec", "", "path to the OpenRPC document")
+	out := flag.String("out", ".", "directory to write the modules to")
+	modules := flag.String("modules", "", "comma-separated modules to generate, all if empty")
+	flag.Parse()
+
+	if err := run(*spec, *out, *modules); err != nil {
+		fmt.Fprintln(os.Stderr, "openrpc-gen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(spec, out, modules string) error {
+	if spec == "" {
+		return fmt.Errorf("-spec is required")
+	}
+	f, err := os.Open(spec)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	doc, err := openrpc.Read(f)
+	if err != nil {
+		return err
+	}
+
+	var wanted []string
+	if modules != "" {
+		wanted = strings.Split(modules, ",")
+	}
+	files, err := Generate(doc, wanted...)
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		path := filepath.Join(out, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, files[name], 0o644); err != nil { //nolint:gosec
+			return err
+		}
+		fmt.Println(path)
+	}
+	return nil
+}
@@ -0,0 +1,296 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	clientbuilder "github.com/celestiaorg/celestia-openrpc/builder"
+	"github.com/celestiaorg/celestia-openrpc/openrpc"
+)
+
+// Sources of a CompatibilityReport.
+const (
+	SourceDiscover = "rpc.discover"
+	SourceNodeInfo = "node.Info"
+)
+
+// CompatibilityReport compares the API declared by the Client with the API
+// served by the node.
+type CompatibilityReport struct {
+	// Source is how the node's API was learned: SourceDiscover if the node
+	// described its methods, or SourceNodeInfo if only its API version is
+	// known, in which case the method lists are empty.
+	Source string
+	// APIVersion is the version of the node's API.
+	APIVersion string
+
+	// Missing are the methods the client declares but the node lacks.
+	Missing []string
+	// Mismatched are the methods whose signatures differ.
+	Mismatched []SignatureMismatch
+	// New are the methods the node offers but the client lacks.
+	New []string
+}
+
+// SignatureMismatch is a method whose signature differs between the client
+// and the node.
+type SignatureMismatch struct {
+	Method string
+	// Client and Node are the signatures, e.g. "(uint64, share.Namespace) *blob.Blob".
+	Client string
+	Node   string
+}
+
+// Compatible reports whether every method of the client is served by the node
+// with the same signature. Without a description of the node's methods, the
+// client is assumed to be compatible.
+func (r *CompatibilityReport) Compatible() bool {
+	return len(r.Missing) == 0 && len(r.Mismatched) == 0
+}
+
+// IncompatibilityError is returned when creating a client that requires
+// compatibility with a node that is not compatible.
+type IncompatibilityError struct {
+	Report *CompatibilityReport
+	// Reason is set if the node is rejected for its API version.
+	Reason string
+}
+
+func (e *IncompatibilityError) Error() string {
+	var problems []string
+	if e.Reason != "" {
+		problems = append(problems, e.Reason)
+	}
+	if n := len(e.Report.Missing); n > 0 {
+		problems = append(problems, fmt.Sprintf("%d missing methods (%s)", n, strings.Join(e.Report.Missing, ", ")))
+	}
+	if n := len(e.Report.Mismatched); n > 0 {
+		methods := make([]string, n)
+		for i, m := range e.Report.Mismatched {
+			methods[i] = m.Method
+		}
+		problems = append(problems, fmt.Sprintf("%d mismatched methods (%s)", n, strings.Join(methods, ", ")))
+	}
+	return "client: node API is incompatible: " + strings.Join(problems, "; ")
+}
+
+// CheckCompatibility compares the API declared by the Client with the one the
+// node describes through rpc.discover. Nodes that do not support rpc.discover
+// are only asked for their API version through Node.Info, which requires the
+// admin permission.
+func (c *Client) CheckCompatibility(ctx context.Context) (*CompatibilityReport, error) {
+	var discoverErr error
+	if c.discover != nil {
+		doc, err := c.discover(ctx)
+		if err == nil {
+			return compare(doc), nil
+		}
+		discoverErr = err
+	}
+
+	info, err := c.Node.Info(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("client: learning the node's API: %w", errors.Join(discoverErr, err))
+	}
+	return &CompatibilityReport{Source: SourceNodeInfo, APIVersion: info.APIVersion}, nil
+}
+
+// RequireCompatibility refuses to create a client for a node whose API is
+// incompatible, as reported by CheckCompatibility, or, if any are given,
+// whose API version is not among apiVersions. Failing to check the API also
+// fails creating the client.
+func RequireCompatibility(apiVersions ...string) Option {
+	return clientbuilder.WithStartupCheck(func(ctx context.Context, client interface{}) error {
+		c, ok := client.(*Client)
+		if !ok {
+			return fmt.Errorf("client: compatibility can only be required of a *Client, not %T", client)
+		}
+		report, err := c.CheckCompatibility(ctx)
+		if err != nil {
+			return err
+		}
+		if !report.Compatible() {
+			return &IncompatibilityError{Report: report}
+		}
+		if len(apiVersions) == 0 {
+			return nil
+		}
+		for _, v := range apiVersions {
+			if v == report.APIVersion {
+				return nil
+			}
+		}
+		return &IncompatibilityError{
+			Report: report,
+			Reason: fmt.Sprintf("API version %q is not one of %v", report.APIVersion, apiVersions),
+		}
+	})
+}
+
+// discover fetches the OpenRPC document of the node.
+func discover(ctx context.Context, dialer *clientbuilder.Dialer) (*openrpc.Document, error) {
+	var api struct {
+		Discover func(context.Context) (json.RawMessage, error) `rpc_method:"rpc.discover"`
+	}
+	closer, err := dialer.Dial(ctx, "rpc", &api)
+	if err != nil {
+		return nil, err
+	}
+	defer closer()
+
+	raw, err := api.Discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return openrpc.Read(bytes.NewReader(raw))
+}
+
+var (
+	ownDocOnce sync.Once
+	ownDoc     *openrpc.Document
+)
+
+// compare builds the report for the document served by the node.
+func compare(node *openrpc.Document) *CompatibilityReport {
+	ownDocOnce.Do(func() {
+		doc, err := openrpc.Emit(&Client{}, openrpc.EmitConfig{})
+		if err != nil {
+			panic(err)
+		}
+		ownDoc = doc
+	})
+
+	report := &CompatibilityReport{Source: SourceDiscover, APIVersion: node.Info.Version}
+	nodeMethods := make(map[string]openrpc.Method, len(node.Methods))
+	for _, m := range node.Methods {
+		nodeMethods[m.Name] = m
+	}
+	ownMethods := make(map[string]bool, len(ownDoc.Methods))
+	for _, own := range ownDoc.Methods {
+		ownMethods[own.Name] = true
+		theirs, ok := nodeMethods[own.Name]
+		if !ok {
+			report.Missing = append(report.Missing, own.Name)
+			continue
+		}
+		if !sameSignature(own, theirs, ownDoc, node) {
+			report.Mismatched = append(report.Mismatched, SignatureMismatch{
+				Method: own.Name,
+				Client: signature(own),
+				Node:   signature(theirs),
+			})
+		}
+	}
+	for name := range nodeMethods {
+		if !ownMethods[name] && !strings.HasPrefix(name, "rpc.") {
+			report.New = append(report.New, name)
+		}
+	}
+	sort.Strings(report.New)
+	return report
+}
+
+func sameSignature(own, theirs openrpc.Method, ownDoc, nodeDoc *openrpc.Document) bool {
+	if len(own.Params) != len(theirs.Params) || (own.Result == nil) != (theirs.Result == nil) {
+		return false
+	}
+	for i := range own.Params {
+		if !sameType(own.Params[i], theirs.Params[i], ownDoc, nodeDoc) {
+			return false
+		}
+	}
+	return own.Result == nil || sameType(*own.Result, *theirs.Result, ownDoc, nodeDoc)
+}
+
+// sameType compares the Go types without their packages, as the client
+// declares its own copies of the types of the node, or else the shapes of the
+// schemas, as types such as state.Balance are aliases whose names differ
+// between the client and the node.
+func sameType(own, theirs openrpc.ContentDescriptor, ownDoc, nodeDoc *openrpc.Document) bool {
+	theirType := theirs.Type()
+	if theirType != "" && localTypeName(own.Type()) == localTypeName(theirType) {
+		return true
+	}
+	ownShape, theirShape := shape(ownDoc, own.Schema, 0), shape(nodeDoc, theirs.Schema, 0)
+	if ownShape != "" && theirShape != "" {
+		return ownShape == theirShape
+	}
+	// without schemas to compare, only the Go types tell
+	return theirType == ""
+}
+
+var (
+	packageName = regexp.MustCompile(`\b\w+\.`)
+	byteName    = regexp.MustCompile(`\bbyte\b`)
+)
+
+// localTypeName strips the packages from a Go type, e.g. "[]*blob.Blob"
+// becomes "[]*Blob", and spells byte as uint8, as reflection does.
+func localTypeName(t string) string {
+	t = packageName.ReplaceAllString(t, "")
+	return byteName.ReplaceAllString(t, "uint8")
+}
+
+// maxShapeDepth bounds the nesting of arrays followed by shape, in case of
+// self-referencing schemas.
+const maxShapeDepth = 8
+
+// shape writes the JSON type of a schema and, for arrays, of their items,
+// e.g. "array<string>", following references into the components of doc.
+// Objects are not described further, as the schemas of nodes describe the Go
+// structs rather than their JSON encoding. It is empty if a type is unknown.
+func shape(doc *openrpc.Document, s *openrpc.Schema, depth int) string {
+	for s != nil && s.Ref != "" {
+		if doc.Components == nil || depth >= maxShapeDepth {
+			return ""
+		}
+		s = doc.Components.Schemas[s.RefName()]
+		depth++
+	}
+	if s == nil || len(s.Type) == 0 {
+		return ""
+	}
+	typ := strings.Join(s.Type, "|")
+	if !s.Type.Is("array") {
+		return typ
+	}
+	if depth >= maxShapeDepth {
+		return ""
+	}
+	items := shape(doc, s.Items, depth+1)
+	if items == "" {
+		return ""
+	}
+	return typ + "<" + items + ">"
+}
+
+// signature writes the signature of a method, e.g.
+// "(uint64, share.Namespace) *blob.Blob".
+func signature(m openrpc.Method) string {
+	typ := func(c openrpc.ContentDescriptor) string {
+		if t := c.Type(); t != "" {
+			return t
+		}
+		if c.Schema != nil && len(c.Schema.Type) > 0 {
+			return strings.Join(c.Schema.Type, "|")
+		}
+		return c.Name
+	}
+
+	params := make([]string, len(m.Params))
+	for i, p := range m.Params {
+		params[i] = typ(p)
+	}
+	sig := "(" + strings.Join(params, ", ") + ")"
+	if m.Result != nil {
+		sig += " " + typ(*m.Result)
+	}
+	return sig
+}
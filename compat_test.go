@@ -0,0 +1,107 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	clientbuilder "github.com/celestiaorg/celestia-openrpc/builder"
+	"github.com/celestiaorg/celestia-openrpc/openrpc"
+	"github.com/celestiaorg/celestia-openrpc/types/header"
+	"github.com/celestiaorg/celestia-openrpc/types/node"
+)
+
+// TestCheckCompatibility ensures that missing, mismatched and new methods of
+// the node are reported.
+func TestCheckCompatibility(t *testing.T) {
+	doc, err := openrpc.Emit(&Client{}, openrpc.EmitConfig{Info: openrpc.Info{Version: "v0.13.0"}})
+	require.NoError(t, err)
+
+	c := &Client{discover: func(context.Context) (*openrpc.Document, error) {
+		return doc, nil
+	}}
+	report, err := c.CheckCompatibility(context.Background())
+	require.NoError(t, err)
+	require.True(t, report.Compatible())
+	require.Equal(t, SourceDiscover, report.Source)
+	require.Equal(t, "v0.13.0", report.APIVersion)
+
+	var methods []openrpc.Method
+	for _, m := range doc.Methods {
+		switch m.Name {
+		case "blob.Submit":
+			continue
+		case "header.GetByHeight":
+			m.Params = []openrpc.ContentDescriptor{{Name: "string", Schema: &openrpc.Schema{}}}
+		}
+		methods = append(methods, m)
+	}
+	methods = append(methods,
+		openrpc.Method{Name: "blob.Subscribe"},
+		openrpc.Method{Name: "rpc.discover"},
+	)
+	doc = &openrpc.Document{OpenRPC: openrpc.Version, Methods: methods}
+
+	report, err = c.CheckCompatibility(context.Background())
+	require.NoError(t, err)
+	require.False(t, report.Compatible())
+	require.Equal(t, []string{"blob.Submit"}, report.Missing)
+	require.Equal(t, []SignatureMismatch{{
+		Method: "header.GetByHeight",
+		Client: "(uint64) *header.ExtendedHeader",
+		Node:   "(string) *header.ExtendedHeader",
+	}}, report.Mismatched)
+	require.Equal(t, []string{"blob.Subscribe"}, report.New)
+}
+
+// TestCheckCompatibility_Node ensures that the types of a node are accepted
+// although they are declared in other packages and, for aliases, under other
+// names than in the client.
+func TestCheckCompatibility_Node(t *testing.T) {
+	f, err := os.Open("testdata/rpc_discover.json")
+	require.NoError(t, err)
+	defer f.Close()
+	doc, err := openrpc.Read(f)
+	require.NoError(t, err)
+
+	report := compare(doc)
+	require.True(t, report.Compatible())
+	require.Empty(t, report.Missing)
+	require.Empty(t, report.Mismatched)
+	require.Empty(t, report.New)
+	require.Equal(t, "v0.13.0", report.APIVersion)
+}
+
+// TestCheckCompatibility_NodeInfo ensures that nodes without rpc.discover are
+// asked for their API version.
+func TestCheckCompatibility_NodeInfo(t *testing.T) {
+	c := &Client{
+		discover: func(context.Context) (*openrpc.Document, error) {
+			return nil, errors.New("method 'rpc.discover' not found")
+		},
+		Node: node.API{Info: func(context.Context) (node.Info, error) {
+			return node.Info{APIVersion: "v0.11.0"}, nil
+		}},
+	}
+	report, err := c.CheckCompatibility(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, &CompatibilityReport{Source: SourceNodeInfo, APIVersion: "v0.11.0"}, report)
+}
+
+// TestRequireCompatibility_OtherClient ensures that requiring compatibility
+// of clients built from other structs fails instead of panicking.
+func TestRequireCompatibility_OtherClient(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	type headerClient struct {
+		Header header.API
+	}
+	_, _, err := clientbuilder.Build[headerClient](context.Background(), srv.URL, RequireCompatibility())
+	require.ErrorContains(t, err, "*client.headerClient")
+}
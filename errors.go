@@ -0,0 +1,44 @@
+package client
+
+import (
+	clientbuilder "github.com/celestiaorg/celestia-openrpc/builder"
+	"github.com/celestiaorg/celestia-openrpc/types/blob"
+	"github.com/celestiaorg/celestia-openrpc/types/header"
+	"github.com/celestiaorg/celestia-openrpc/types/state"
+)
+
+// Errors returns the registry of the known errors of the node, which NewClient
+// decodes errors into, so that e.g. errors.Is(err, blob.ErrBlobNotFound)
+// holds for blobs missing on the node. celestia-node sends its errors without
+// registered JSON-RPC error codes, so they are recognized by their messages.
+func Errors() *clientbuilder.ErrorRegistry {
+	return clientbuilder.NewErrorRegistry(
+		clientbuilder.KnownError{
+			Err:      blob.ErrBlobNotFound,
+			Messages: []string{blob.ErrBlobNotFound.Error()},
+		},
+		clientbuilder.KnownError{
+			Err:      header.ErrNotFound,
+			Messages: []string{header.ErrNotFound.Error()},
+		},
+		clientbuilder.KnownError{
+			Err:      header.ErrHeightFromFuture,
+			Messages: []string{"from the future"},
+			Parse: func(msg string) error {
+				return header.ParseHeightFromFutureError(msg)
+			},
+		},
+		clientbuilder.KnownError{
+			Err:      state.ErrInsufficientFunds,
+			Messages: []string{"insufficient funds"},
+		},
+		clientbuilder.KnownError{
+			Err:      state.ErrTxTimeout,
+			Messages: []string{"timed out waiting for tx"},
+		},
+		clientbuilder.KnownError{
+			Err:      header.ErrNotSynced,
+			Messages: []string{"not synced", "syncer is syncing"},
+		},
+	)
+}
@@ -30,21 +30,17 @@ func main() {
 	}
 }
 
-const AuthKey = "Authorization"
-
 type Client struct {
 	Blob blob.API
 }
 
 // SubmitBlob submits a blob containing "Hello, World!" to the 0xDEADBEEF namespace. It uses the default signer on the running node.
 func SubmitBlob(ctx context.Context, url string, token string) error {
-	var client Client
-	constructedClient, err := clientbuilder.NewClient(ctx, url, token, client)
+	client, closer, err := clientbuilder.Build[Client](ctx, url, clientbuilder.WithToken(token))
 	if err != nil {
 		return err
 	}
-
-	client = constructedClient.(Client)
+	defer closer()
 
 	// let's post to 0xDEADBEEF namespace
 	namespace, err := share.NewBlobNamespaceV0([]byte{0xDE, 0xAD, 0xBE, 0xEF})
@@ -0,0 +1,355 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/filecoin-project/go-jsonrpc"
+
+	clientbuilder "github.com/celestiaorg/celestia-openrpc/builder"
+	"github.com/celestiaorg/celestia-openrpc/openrpc"
+)
+
+// ErrNoEndpoint is returned by a failover client when none of its endpoints
+// can be reached.
+var ErrNoEndpoint = errors.New("client: no endpoint available")
+
+// FailoverConfig configures a client that is spread across several nodes.
+type FailoverConfig struct {
+	// Endpoints are the addresses of the nodes, in order of preference.
+	Endpoints []string
+	// HealthCheckInterval is how often the endpoints are checked. Defaults to
+	// 10 seconds.
+	HealthCheckInterval time.Duration
+	// MaxSyncLag is how many heights a node may be behind the network head and
+	// still be considered synced.
+	MaxSyncLag uint64
+}
+
+// NewFailoverClient returns a Client that routes every call to a healthy node
+// among the configured endpoints. A node is healthy if Node.Ready reports true
+// and Header.SyncState is within MaxSyncLag of the network head.
+//
+// Reads are retried on the next healthy node if the connection fails. Writes,
+// i.e. methods that require more than the read permission, are pinned to a
+// single node and never retried elsewhere, so a transaction is never
+// submitted twice. The pinned node only changes once a health check finds it
+// unhealthy.
+//
+// It returns an error wrapping ErrNoEndpoint if none of the endpoints can be
+// reached. Reads fall back to nodes that can be reached but are unhealthy
+// when no healthy node answers.
+//
+// The permissions of the token are learned once from the first node reached.
+// CheckCompatibility describes the pinned node, or else the node reads go to
+// first.
+func NewFailoverClient(ctx context.Context, cfg FailoverConfig, token string, opts ...Option) (*Client, error) {
+	if len(cfg.Endpoints) == 0 {
+		return nil, errors.New("client: no endpoints configured")
+	}
+	if cfg.HealthCheckInterval == 0 {
+		cfg.HealthCheckInterval = 10 * time.Second
+	}
+
+	f := &failover{
+		cfg:   cfg,
+		token: token,
+		opts:  opts,
+	}
+	for _, addr := range cfg.Endpoints {
+		f.endpoints = append(f.endpoints, &endpoint{addr: addr})
+	}
+	// connections and health checks outlive ctx, which only bounds the
+	// construction
+	f.ctx, f.cancel = context.WithCancel(context.Background())
+	f.checkHealth(ctx)
+	if !f.reachable() {
+		err := f.err()
+		f.close()
+		return nil, fmt.Errorf("%w: %w", ErrNoEndpoint, err)
+	}
+
+	var client Client
+	// the token is the same for every endpoint, so are its permissions,
+	// unless they are only known from the node that verified it
+	for _, ep := range f.endpoints {
+		if ep.client != nil {
+			client.perms = ep.client.perms
+			break
+		}
+	}
+	client.discover = f.discover
+	methods, err := clientbuilder.Methods(&client)
+	if err != nil {
+		f.close()
+		return nil, err
+	}
+	for _, m := range methods {
+		m := m
+		m.Value(&client).Set(reflect.MakeFunc(m.Type, func(args []reflect.Value) []reflect.Value {
+			if m.Perm == clientbuilder.PermRead {
+				return f.callRead(m, args)
+			}
+			return f.callPinned(m, args)
+		}))
+	}
+
+	f.wg.Add(1)
+	go f.run()
+	client.closer.Register(f.close)
+	return &client, nil
+}
+
+type endpoint struct {
+	addr    string
+	client  *Client
+	healthy bool
+	err     error
+	// version is bumped whenever a call marks the endpoint unhealthy.
+	version uint64
+}
+
+type failover struct {
+	cfg   FailoverConfig
+	token string
+	opts  []Option
+
+	mu        sync.Mutex
+	endpoints []*endpoint
+	pinned    *endpoint
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func (f *failover) run() {
+	defer f.wg.Done()
+
+	ticker := time.NewTicker(f.cfg.HealthCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			f.checkHealth(f.ctx)
+		case <-f.ctx.Done():
+			return
+		}
+	}
+}
+
+func (f *failover) close() {
+	f.cancel()
+	f.wg.Wait()
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, ep := range f.endpoints {
+		if ep.client != nil {
+			ep.client.Close()
+		}
+	}
+}
+
+// checkHealth checks all endpoints concurrently, dialing the ones that have
+// not been reached yet. The connections themselves are bound to f.ctx.
+//
+// The endpoints are probed without holding f.mu, so the results are only
+// merged into those whose health has not been changed by a failed call in the
+// meantime.
+func (f *failover) checkHealth(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, f.cfg.HealthCheckInterval)
+	defer cancel()
+
+	type result struct {
+		client  *Client
+		dialed  bool
+		version uint64
+		err     error
+	}
+	f.mu.Lock()
+	results := make([]result, len(f.endpoints))
+	for i, ep := range f.endpoints {
+		results[i] = result{client: ep.client, version: ep.version}
+	}
+	f.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for i := range results {
+		wg.Add(1)
+		go func(addr string, r *result) {
+			defer wg.Done()
+			if r.client == nil {
+				r.client, r.err = NewClient(f.ctx, addr, f.token, f.opts...)
+				if r.err != nil {
+					return
+				}
+				r.dialed = true
+			}
+			r.err = f.probe(ctx, r.client)
+		}(f.endpoints[i].addr, &results[i])
+	}
+	wg.Wait()
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i, ep := range f.endpoints {
+		r := results[i]
+		if r.dialed {
+			ep.client = r.client
+		}
+		if ep.version != r.version {
+			continue
+		}
+		ep.healthy = r.err == nil
+		ep.err = r.err
+	}
+	if f.pinned != nil && !f.pinned.healthy {
+		f.pinned = nil
+	}
+}
+
+// reachable reports whether a node answered the last health check, healthy
+// or not.
+func (f *failover) reachable() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, ep := range f.endpoints {
+		if ep.client != nil && !isConnectionError(ep.err) {
+			return true
+		}
+	}
+	return false
+}
+
+// err returns the errors of the endpoints.
+func (f *failover) err() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	errs := make([]error, 0, len(f.endpoints))
+	for _, ep := range f.endpoints {
+		errs = append(errs, fmt.Errorf("%s: %w", ep.addr, ep.err))
+	}
+	return errors.Join(errs...)
+}
+
+func (f *failover) probe(ctx context.Context, c *Client) error {
+	ready, err := c.Node.Ready(ctx)
+	if err != nil {
+		return err
+	}
+	if !ready {
+		return errors.New("node is not ready")
+	}
+	state, err := c.Header.SyncState(ctx)
+	if err != nil {
+		return err
+	}
+	if state.ToHeight > state.Height+f.cfg.MaxSyncLag {
+		return fmt.Errorf("node is syncing: at height %d of %d", state.Height, state.ToHeight)
+	}
+	return nil
+}
+
+type candidate struct {
+	ep     *endpoint
+	client *Client
+}
+
+// candidates returns the dialed endpoints, healthy ones first, each group in
+// order of preference.
+func (f *failover) candidates() []candidate {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var healthy, unhealthy []candidate
+	for _, ep := range f.endpoints {
+		switch {
+		case ep.client == nil:
+		case ep.healthy:
+			healthy = append(healthy, candidate{ep, ep.client})
+		default:
+			unhealthy = append(unhealthy, candidate{ep, ep.client})
+		}
+	}
+	return append(healthy, unhealthy...)
+}
+
+func (f *failover) markUnhealthy(ep *endpoint, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	ep.healthy = false
+	ep.err = err
+	ep.version++
+}
+
+func (f *failover) callRead(m clientbuilder.Method, args []reflect.Value) []reflect.Value {
+	results := m.ErrorResults(ErrNoEndpoint)
+	for _, c := range f.candidates() {
+		results = call(m, c.client, args)
+		err := m.Err(results)
+		if !isConnectionError(err) {
+			return results
+		}
+		f.markUnhealthy(c.ep, err)
+	}
+	return results
+}
+
+func (f *failover) callPinned(m clientbuilder.Method, args []reflect.Value) []reflect.Value {
+	f.mu.Lock()
+	if f.pinned == nil {
+		for _, ep := range f.endpoints {
+			if ep.client != nil && ep.healthy {
+				f.pinned = ep
+				break
+			}
+		}
+	}
+	var pinned *Client
+	if f.pinned != nil {
+		pinned = f.pinned.client
+	}
+	f.mu.Unlock()
+
+	if pinned == nil {
+		return m.ErrorResults(ErrNoEndpoint)
+	}
+	return call(m, pinned, args)
+}
+
+// call calls the method on c, passing the variadic arguments as given.
+func call(m clientbuilder.Method, c *Client, args []reflect.Value) []reflect.Value {
+	if m.Type.IsVariadic() {
+		return m.Value(c).CallSlice(args)
+	}
+	return m.Value(c).Call(args)
+}
+
+// discover fetches the OpenRPC document of the pinned endpoint, which writes
+// go to, or else of the endpoint reads go to first.
+func (f *failover) discover(ctx context.Context) (*openrpc.Document, error) {
+	f.mu.Lock()
+	var c *Client
+	if f.pinned != nil {
+		c = f.pinned.client
+	}
+	f.mu.Unlock()
+	if c == nil {
+		candidates := f.candidates()
+		if len(candidates) == 0 {
+			return nil, ErrNoEndpoint
+		}
+		c = candidates[0].client
+	}
+	return c.discover(ctx)
+}
+
+func isConnectionError(err error) bool {
+	var connErr *jsonrpc.RPCConnectionError
+	return errors.As(err, &connErr)
+}
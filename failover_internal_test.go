@@ -0,0 +1,65 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/celestiaorg/go-header/sync"
+	"github.com/stretchr/testify/require"
+
+	"github.com/celestiaorg/celestia-openrpc/types/header"
+	"github.com/celestiaorg/celestia-openrpc/types/node"
+)
+
+// TestCheckHealth_KeepsConcurrentFailures ensures that an endpoint marked
+// unhealthy by a failed call while it is probed is not reported healthy by
+// the outdated probe.
+func TestCheckHealth_KeepsConcurrentFailures(t *testing.T) {
+	probing, release := make(chan struct{}), make(chan struct{})
+	c := &Client{
+		Node: node.API{
+			Ready: func(context.Context) (bool, error) {
+				close(probing)
+				<-release
+				return true, nil
+			},
+		},
+		Header: header.API{
+			SyncState: func(context.Context) (sync.State, error) {
+				return sync.State{}, nil
+			},
+		},
+	}
+	ep := &endpoint{addr: "http://node", client: c, healthy: true}
+	f := &failover{
+		cfg:       FailoverConfig{HealthCheckInterval: time.Second},
+		endpoints: []*endpoint{ep},
+		pinned:    ep,
+		ctx:       context.Background(),
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		f.checkHealth(context.Background())
+	}()
+	<-probing
+	failure := errors.New("connection reset")
+	f.markUnhealthy(ep, failure)
+	close(release)
+	<-done
+
+	require.False(t, ep.healthy)
+	require.Equal(t, failure, ep.err)
+	require.Same(t, c, ep.client)
+	require.Nil(t, f.pinned)
+
+	// the next check is not outdated
+	probing, release = make(chan struct{}), make(chan struct{})
+	close(release)
+	f.checkHealth(context.Background())
+	require.True(t, ep.healthy)
+	require.NoError(t, ep.err)
+}
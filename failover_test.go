@@ -0,0 +1,184 @@
+package client_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	client "github.com/celestiaorg/celestia-openrpc"
+	"github.com/celestiaorg/celestia-openrpc/testnode"
+	"github.com/celestiaorg/celestia-openrpc/types/blob"
+	"github.com/celestiaorg/celestia-openrpc/types/share"
+)
+
+// endpoint serves a testnode over HTTP and can be taken down and brought back
+// up, counting the calls it serves by method.
+type endpoint struct {
+	srv  *httptest.Server
+	up   atomic.Bool
+	mu   sync.Mutex
+	hits map[string]int
+}
+
+func newEndpoint(t *testing.T) *endpoint {
+	t.Helper()
+	node, err := testnode.New()
+	require.NoError(t, err)
+	t.Cleanup(node.Close)
+	target, err := url.Parse("http" + strings.TrimPrefix(node.URL(), "ws"))
+	require.NoError(t, err)
+	proxy := httputil.NewSingleHostReverseProxy(target)
+
+	e := &endpoint{hits: map[string]int{}}
+	e.up.Store(true)
+	e.srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !e.up.Load() {
+			// drop the connection, as a dead node would
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err == nil {
+				conn.Close()
+			}
+			return
+		}
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		var req struct {
+			Method string `json:"method"`
+		}
+		_ = json.Unmarshal(body, &req)
+		e.mu.Lock()
+		e.hits[req.Method]++
+		e.mu.Unlock()
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		proxy.ServeHTTP(w, r)
+	}))
+	t.Cleanup(e.srv.Close)
+	return e
+}
+
+func (e *endpoint) calls(method string) int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.hits[method]
+}
+
+func newFailoverClient(t *testing.T, ctx context.Context, endpoints ...*endpoint) (*client.Client, error) {
+	t.Helper()
+	cfg := client.FailoverConfig{HealthCheckInterval: 50 * time.Millisecond}
+	for _, e := range endpoints {
+		cfg.Endpoints = append(cfg.Endpoints, e.srv.URL)
+	}
+	c, err := client.NewFailoverClient(ctx, cfg, "")
+	if err == nil {
+		t.Cleanup(c.Close)
+	}
+	return c, err
+}
+
+func submit(ctx context.Context, c *client.Client) error {
+	ns, err := share.NewBlobNamespaceV0([]byte("failover"))
+	if err != nil {
+		return err
+	}
+	b, err := blob.NewBlobV0(ns, []byte("data"))
+	if err != nil {
+		return err
+	}
+	_, err = c.Blob.Submit(ctx, []*blob.Blob{b}, blob.DefaultGasPrice())
+	return err
+}
+
+func TestFailover_PinnedEndpointDies(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	first, second := newEndpoint(t), newEndpoint(t)
+	c, err := newFailoverClient(t, ctx, first, second)
+	require.NoError(t, err)
+
+	require.NoError(t, submit(ctx, c))
+	require.Equal(t, 1, first.calls("blob.Submit"))
+	_, err = c.Header.NetworkHead(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, first.calls("header.NetworkHead"))
+
+	first.up.Store(false)
+	// reads are retried on the next node right away
+	_, err = c.Header.NetworkHead(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, second.calls("header.NetworkHead"))
+
+	// writes move once a health check finds the pinned node unhealthy
+	require.Eventually(t, func() bool {
+		return submit(ctx, c) == nil
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, 1, second.calls("blob.Submit"))
+	require.Equal(t, 1, first.calls("blob.Submit"))
+
+	// the API is learned from the node writes go to
+	_, _ = c.CheckCompatibility(ctx)
+	require.Equal(t, 1, second.calls("rpc.discover"))
+	require.Zero(t, first.calls("rpc.discover"))
+}
+
+func TestFailover_Recovery(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	first, second := newEndpoint(t), newEndpoint(t)
+	first.up.Store(false)
+	c, err := newFailoverClient(t, ctx, first, second)
+	require.NoError(t, err)
+
+	_, err = c.Header.NetworkHead(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, second.calls("header.NetworkHead"))
+
+	// the preferred node is used again once a health check finds it healthy
+	first.up.Store(true)
+	require.Eventually(t, func() bool {
+		_, err := c.Header.NetworkHead(ctx)
+		return err == nil && first.calls("header.NetworkHead") > 0
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestFailover_AllEndpointsDown(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	first, second := newEndpoint(t), newEndpoint(t)
+	first.up.Store(false)
+	second.up.Store(false)
+	_, err := newFailoverClient(t, ctx, first, second)
+	require.ErrorIs(t, err, client.ErrNoEndpoint)
+	require.ErrorContains(t, err, first.srv.URL)
+
+	first.up.Store(true)
+	second.up.Store(true)
+	c, err := newFailoverClient(t, ctx, first, second)
+	require.NoError(t, err)
+	first.up.Store(false)
+	second.up.Store(false)
+
+	_, err = c.Header.NetworkHead(ctx)
+	require.Error(t, err)
+	require.Eventually(t, func() bool {
+		return errors.Is(submit(ctx, c), client.ErrNoEndpoint)
+	}, 5*time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,7 @@
+// Package blobindex lets the packages of this module set the index of a blob,
+// which callers of the blob package cannot set.
+package blobindex
+
+// Set sets the index of the first share of b, a *blob.Blob, in the extended
+// data square. It is installed by the blob package, which imports this one.
+var Set func(b interface{}, index int)
@@ -0,0 +1,148 @@
+// Package metrics instruments the calls of a client with Prometheus metrics.
+//
+//	m := metrics.New("myapp")
+//	prometheus.MustRegister(m)
+//	c, err := client.NewClient(ctx, addr, token, client.WithInterceptors(m.Interceptor()))
+package metrics
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	clientbuilder "github.com/celestiaorg/celestia-openrpc/builder"
+)
+
+// Metrics collects metrics about the calls going through its interceptor. It
+// is a prometheus.Collector, to be registered with a registry.
+type Metrics struct {
+	requests        *prometheus.CounterVec
+	errors          *prometheus.CounterVec
+	latency         *prometheus.HistogramVec
+	inFlight        *prometheus.GaugeVec
+	subscriptionLag *prometheus.HistogramVec
+}
+
+var _ prometheus.Collector = (*Metrics)(nil)
+
+// New returns metrics whose names are prefixed with namespace, if not empty,
+// and "celestia_client".
+func New(namespace string) *Metrics {
+	opts := func(name, help string) prometheus.Opts {
+		return prometheus.Opts{
+			Namespace: namespace,
+			Subsystem: "celestia_client",
+			Name:      name,
+			Help:      help,
+		}
+	}
+	labels := []string{"module", "method"}
+
+	return &Metrics{
+		requests: prometheus.NewCounterVec(
+			prometheus.CounterOpts(opts("requests_total", "Number of calls to the node.")),
+			labels,
+		),
+		errors: prometheus.NewCounterVec(
+			prometheus.CounterOpts(opts("errors_total", "Number of calls to the node that failed, by error class.")),
+			append(labels, "class"),
+		),
+		latency: prometheus.NewHistogramVec(
+			histogramOpts(opts("request_duration_seconds", "Duration of calls to the node.")),
+			labels,
+		),
+		inFlight: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts(opts("requests_in_flight", "Number of calls to the node in progress.")),
+			labels,
+		),
+		subscriptionLag: prometheus.NewHistogramVec(
+			histogramOpts(opts(
+				"subscription_lag_seconds",
+				"Time items of subscriptions wait until they are received from the channel.",
+			)),
+			labels,
+		),
+	}
+}
+
+func histogramOpts(opts prometheus.Opts) prometheus.HistogramOpts {
+	return prometheus.HistogramOpts{
+		Namespace: opts.Namespace,
+		Subsystem: opts.Subsystem,
+		Name:      opts.Name,
+		Help:      opts.Help,
+		Buckets:   prometheus.DefBuckets,
+	}
+}
+
+func (m *Metrics) collectors() []prometheus.Collector {
+	return []prometheus.Collector{m.requests, m.errors, m.latency, m.inFlight, m.subscriptionLag}
+}
+
+// Describe implements prometheus.Collector.
+func (m *Metrics) Describe(ch chan<- *prometheus.Desc) {
+	for _, c := range m.collectors() {
+		c.Describe(ch)
+	}
+}
+
+// Collect implements prometheus.Collector.
+func (m *Metrics) Collect(ch chan<- prometheus.Metric) {
+	for _, c := range m.collectors() {
+		c.Collect(ch)
+	}
+}
+
+// Interceptor returns the interceptor recording the metrics, to be installed
+// with client.WithInterceptors.
+func (m *Metrics) Interceptor() clientbuilder.Interceptor {
+	return func(ctx context.Context, call *clientbuilder.Call, next clientbuilder.Invoker) error {
+		module, method := call.Method.Namespace, call.Method.Name
+		inFlight := m.inFlight.WithLabelValues(module, method)
+
+		inFlight.Inc()
+		err := next(ctx, call)
+		inFlight.Dec()
+
+		m.requests.WithLabelValues(module, method).Inc()
+		m.latency.WithLabelValues(module, method).Observe(call.Duration.Seconds())
+		if err != nil {
+			m.errors.WithLabelValues(module, method, clientbuilder.Classify(err).String()).Inc()
+			return err
+		}
+
+		if call.Result != nil && reflect.TypeOf(call.Result).Kind() == reflect.Chan {
+			call.Result = m.relay(ctx, call.Result, m.subscriptionLag.WithLabelValues(module, method))
+		}
+		return nil
+	}
+}
+
+// relay forwards the items of a subscription channel, observing how long each
+// waits for the receiver. It stops once ctx, the ctx of the subscribing call,
+// is done, so that an abandoned channel does not pin the subscription.
+func (m *Metrics) relay(ctx context.Context, ch interface{}, lag prometheus.Observer) interface{} {
+	in := reflect.ValueOf(ch)
+	out := reflect.MakeChan(reflect.ChanOf(reflect.BothDir, in.Type().Elem()), 0)
+	done := reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())}
+	go func() {
+		defer out.Close()
+		recv := []reflect.SelectCase{{Dir: reflect.SelectRecv, Chan: in}, done}
+		send := []reflect.SelectCase{{Dir: reflect.SelectSend, Chan: out}, done}
+		for {
+			chosen, v, ok := reflect.Select(recv)
+			if chosen != 0 || !ok {
+				return
+			}
+			start := time.Now()
+			send[0].Send = v
+			if chosen, _, _ := reflect.Select(send); chosen != 0 {
+				return
+			}
+			lag.Observe(time.Since(start).Seconds())
+		}
+	}()
+	return out.Convert(in.Type()).Interface()
+}
@@ -0,0 +1,115 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	clientbuilder "github.com/celestiaorg/celestia-openrpc/builder"
+)
+
+type testModule struct {
+	Get       func(context.Context) (int, error)        `perm:"read"`
+	Subscribe func(context.Context) (<-chan int, error) `perm:"read"`
+}
+
+type testClient struct {
+	Test testModule
+}
+
+func TestMetrics(t *testing.T) {
+	m := New("")
+	reg := prometheus.NewRegistry()
+	require.NoError(t, reg.Register(m))
+
+	calls := 0
+	items := make(chan int, 1)
+	items <- 1
+	close(items)
+	client := &testClient{Test: testModule{
+		Get: func(context.Context) (int, error) {
+			calls++
+			if calls > 1 {
+				return 0, errors.New("websocket connection closed")
+			}
+			return 1, nil
+		},
+		Subscribe: func(context.Context) (<-chan int, error) {
+			return items, nil
+		},
+	}}
+	dialer, err := clientbuilder.NewDialer(
+		context.Background(),
+		"http://localhost:26658",
+		"",
+		clientbuilder.WithInterceptors(m.Interceptor()),
+	)
+	require.NoError(t, err)
+	require.NoError(t, dialer.Wrap(client))
+
+	_, err = client.Test.Get(context.Background())
+	require.NoError(t, err)
+	_, err = client.Test.Get(context.Background())
+	require.Error(t, err)
+
+	sub, err := client.Test.Subscribe(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, <-sub)
+	_, ok := <-sub
+	require.False(t, ok)
+
+	require.Equal(t, 2.0, testutil.ToFloat64(m.requests.WithLabelValues("test", "Get")))
+	require.Equal(t, 1.0, testutil.ToFloat64(m.errors.WithLabelValues("test", "Get", "connection")))
+	require.Equal(t, 0.0, testutil.ToFloat64(m.inFlight.WithLabelValues("test", "Get")))
+	require.Equal(t, 2, testutil.CollectAndCount(m.latency))
+	require.Equal(t, 1, testutil.CollectAndCount(m.subscriptionLag))
+
+	problems, err := testutil.GatherAndLint(reg)
+	require.NoError(t, err)
+	require.Empty(t, problems)
+}
+
+// TestMetrics_AbandonedSubscription ensures that the relay of a subscription
+// stops once the ctx of the subscribing call is done, even if the channel is
+// no longer read.
+func TestMetrics_AbandonedSubscription(t *testing.T) {
+	m := New("")
+	items := make(chan int)
+	client := &testClient{Test: testModule{
+		Subscribe: func(context.Context) (<-chan int, error) {
+			return items, nil
+		},
+	}}
+	dialer, err := clientbuilder.NewDialer(
+		context.Background(),
+		"http://localhost:26658",
+		"",
+		clientbuilder.WithInterceptors(m.Interceptor()),
+	)
+	require.NoError(t, err)
+	require.NoError(t, dialer.Wrap(client))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	sub, err := client.Test.Subscribe(ctx)
+	require.NoError(t, err)
+	// the relay waits for the subscriber to read the item
+	items <- 1
+
+	cancel()
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case _, ok := <-sub:
+			if !ok {
+				return
+			}
+		case <-timeout:
+			t.Fatal("subscription is still relayed")
+		}
+	}
+}
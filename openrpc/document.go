@@ -0,0 +1,190 @@
+// Package openrpc models OpenRPC 1.x documents, as published by celestia-node
+// to describe its API.
+package openrpc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// Version is the OpenRPC version of the documents written by this package.
+const Version = "1.2.6"
+
+// Document is an OpenRPC document.
+type Document struct {
+	OpenRPC    string      `json:"openrpc"`
+	Info       Info        `json:"info"`
+	Methods    []Method    `json:"methods"`
+	Components *Components `json:"components,omitempty"`
+}
+
+// Info describes the API.
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// Method describes a method, named "<module>.<Method>", e.g. "blob.Get".
+type Method struct {
+	Name           string              `json:"name"`
+	Summary        string              `json:"summary,omitempty"`
+	Description    string              `json:"description,omitempty"`
+	ParamStructure string              `json:"paramStructure,omitempty"`
+	Params         []ContentDescriptor `json:"params"`
+	Result         *ContentDescriptor  `json:"result,omitempty"`
+	Deprecated     bool                `json:"deprecated,omitempty"`
+
+	// Perm is the permission required to call the method. celestia-node
+	// states it in the description instead, see Permission.
+	Perm string `json:"x-perm,omitempty"`
+}
+
+var authLevel = regexp.MustCompile(`(?m)^Auth level: *(\w+)\s*$`)
+
+// Permission returns the permission required to call the method, taken from
+// the x-perm extension or else the "Auth level: <perm>" line of the
+// description. It is empty if neither is present.
+func (m Method) Permission() string {
+	if m.Perm != "" {
+		return m.Perm
+	}
+	if match := authLevel.FindStringSubmatch(m.Description); match != nil {
+		return match[1]
+	}
+	return ""
+}
+
+// Doc returns the documentation of the method without the auth level line.
+func (m Method) Doc() string {
+	doc := m.Description
+	if doc == "" {
+		doc = m.Summary
+	}
+	return strings.TrimSpace(authLevel.ReplaceAllString(doc, ""))
+}
+
+// Module splits the name of the method into its module and method name.
+func (m Method) Module() (module, name string, err error) {
+	module, name, ok := strings.Cut(m.Name, ".")
+	if !ok || module == "" || name == "" {
+		return "", "", fmt.Errorf("method name %q is not of the form <module>.<Method>", m.Name)
+	}
+	return module, name, nil
+}
+
+// ContentDescriptor describes a parameter or result.
+type ContentDescriptor struct {
+	Name        string  `json:"name"`
+	Summary     string  `json:"summary,omitempty"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+
+	// GoType is the Go type of the value, e.g. "*blob.Blob". celestia-node
+	// uses the Go type as the name instead, see Type.
+	GoType string `json:"x-go-type,omitempty"`
+}
+
+// Type returns the Go type of the value as given by the document, which is
+// GoType if set and else the name, should it look like a Go type.
+func (c ContentDescriptor) Type() string {
+	if c.GoType != "" {
+		return c.GoType
+	}
+	if goType.MatchString(c.Name) {
+		return c.Name
+	}
+	return ""
+}
+
+var goType = regexp.MustCompile(
+	`^(\*|\[\]|<-chan )*(\w+\.\w+|bool|string|byte|u?int(8|16|32|64)?|float(32|64))$`,
+)
+
+// Components holds the schemas referenced by the methods.
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas,omitempty"`
+}
+
+// Schema is a JSON schema.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Type                 Types              `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	ContentEncoding      string             `json:"contentEncoding,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Examples             []interface{}      `json:"examples,omitempty"`
+}
+
+// UnmarshalJSON decodes a schema, accepting the boolean schemas true and
+// false, as nodes give for additionalProperties, as an empty schema.
+func (s *Schema) UnmarshalJSON(data []byte) error {
+	var b bool
+	if err := json.Unmarshal(data, &b); err == nil {
+		*s = Schema{}
+		return nil
+	}
+	type schema Schema
+	return json.Unmarshal(data, (*schema)(s))
+}
+
+// Types is the type of a schema, which JSON schema allows to be a single type
+// or a list of types.
+type Types []string
+
+// Is reports whether the list contains the type.
+func (t Types) Is(typ string) bool {
+	for _, v := range t {
+		if v == typ {
+			return true
+		}
+	}
+	return false
+}
+
+func (t Types) MarshalJSON() ([]byte, error) {
+	if len(t) == 1 {
+		return json.Marshal(t[0])
+	}
+	return json.Marshal([]string(t))
+}
+
+func (t *Types) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*t = Types{single}
+		return nil
+	}
+	return json.Unmarshal(data, (*[]string)(t))
+}
+
+// RefName returns the name of the component schema referenced by s, or an
+// empty string if s is not a reference.
+func (s *Schema) RefName() string {
+	if s == nil {
+		return ""
+	}
+	return strings.TrimPrefix(s.Ref, "#/components/schemas/")
+}
+
+// Read decodes a document.
+func Read(r io.Reader) (*Document, error) {
+	var doc Document
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("decoding OpenRPC document: %w", err)
+	}
+	if !strings.HasPrefix(doc.OpenRPC, "1.") {
+		return nil, errors.New("not an OpenRPC 1.x document")
+	}
+	return &doc, nil
+}
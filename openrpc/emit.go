@@ -0,0 +1,222 @@
+package openrpc
+
+import (
+	"context"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	clientbuilder "github.com/celestiaorg/celestia-openrpc/builder"
+)
+
+// EmitConfig configures Emit.
+type EmitConfig struct {
+	Info Info
+	// Docs maps the names of methods, e.g. "blob.Get", to their documentation.
+	Docs map[string]string
+	// WireTypes maps types with a custom JSON encoding to types encoded alike
+	// without one, from which their schema is derived. Types with a custom
+	// encoding that are not listed get a schema without a type. Defaults to
+	// DefaultWireTypes.
+	WireTypes map[reflect.Type]reflect.Type
+}
+
+// Emit describes the methods of client, a pointer to a struct of modules such
+// as client.Client, as an OpenRPC document. Parameters and results are named
+// by their Go type, as done by celestia-node, and their schemas derived from
+// the types. Structs are described once in the components of the document.
+func Emit(client interface{}, cfg EmitConfig) (*Document, error) {
+	methods, err := clientbuilder.Methods(client)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.WireTypes == nil {
+		cfg.WireTypes = DefaultWireTypes()
+	}
+
+	s := &schemas{wire: cfg.WireTypes, defs: map[string]*Schema{}}
+	doc := &Document{OpenRPC: Version, Info: cfg.Info}
+	for _, m := range methods {
+		doc.Methods = append(doc.Methods, s.method(m, cfg.Docs[m.String()]))
+	}
+	if len(s.defs) > 0 {
+		doc.Components = &Components{Schemas: s.defs}
+	}
+	return doc, nil
+}
+
+func (s *schemas) method(m clientbuilder.Method, doc string) Method {
+	description := fmt.Sprintf("Auth level: %s", m.Perm)
+	if doc != "" {
+		description = doc + "\n\n" + description
+	}
+	method := Method{
+		Name:           m.String(),
+		Description:    description,
+		ParamStructure: "by-position",
+		Params:         []ContentDescriptor{},
+		Perm:           string(m.Perm),
+	}
+
+	for i := 0; i < m.Type.NumIn(); i++ {
+		in := m.Type.In(i)
+		if i == 0 && in == contextType {
+			continue
+		}
+		method.Params = append(method.Params, ContentDescriptor{
+			Name:     typeName(in),
+			Required: true,
+			Schema:   s.of(in),
+			GoType:   typeName(in),
+		})
+	}
+	if m.Type.NumOut() > 1 {
+		out := m.Type.Out(0)
+		method.Result = &ContentDescriptor{
+			Name:   typeName(out),
+			Schema: s.of(out),
+			GoType: typeName(out),
+		}
+	}
+	return method
+}
+
+var (
+	contextType       = reflect.TypeOf((*context.Context)(nil)).Elem()
+	timeType          = reflect.TypeOf(time.Time{})
+	rawMessageType    = reflect.TypeOf(json.RawMessage{})
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// typeName writes the type as in Go source, e.g. "[]*blob.Blob".
+func typeName(t reflect.Type) string {
+	return t.String()
+}
+
+// implements reports whether values of t or pointers to them implement iface.
+func implements(t, iface reflect.Type) bool {
+	return t.Implements(iface) || reflect.PointerTo(t).Implements(iface)
+}
+
+// schemas derives schemas from Go types, collecting the definitions of named
+// structs.
+type schemas struct {
+	wire map[reflect.Type]reflect.Type
+	defs map[string]*Schema
+}
+
+func (s *schemas) of(t reflect.Type) *Schema {
+	switch {
+	case t == timeType:
+		return &Schema{Type: Types{"string"}, Format: "date-time"}
+	case t == rawMessageType:
+		return &Schema{}
+	}
+	if wire, ok := s.wire[t]; ok {
+		return s.named(t, wire)
+	}
+	if t.Kind() != reflect.Pointer {
+		switch {
+		case implements(t, jsonMarshalerType):
+			return &Schema{Title: typeName(t), Description: "Custom JSON encoding."}
+		case implements(t, textMarshalerType):
+			return &Schema{Type: Types{"string"}, Title: typeName(t)}
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Chan:
+		return s.of(t.Elem())
+	case reflect.Bool:
+		return &Schema{Type: Types{"boolean"}}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: Types{"integer"}, Format: t.Kind().String()}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: Types{"number"}}
+	case reflect.String:
+		return &Schema{Type: Types{"string"}}
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: Types{"string"}, ContentEncoding: "base64"}
+		}
+		return &Schema{Type: Types{"array"}, Items: s.of(t.Elem())}
+	case reflect.Array:
+		return &Schema{Type: Types{"array"}, Items: s.of(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: Types{"object"}, AdditionalProperties: s.of(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return s.object(t)
+		}
+		return s.named(t, t)
+	}
+	// interfaces and the like may hold anything
+	return &Schema{}
+}
+
+// named returns a reference to the definition of the named type t, whose
+// encoding is that of wire.
+func (s *schemas) named(t, wire reflect.Type) *Schema {
+	name := typeName(t)
+	if _, ok := s.defs[name]; !ok {
+		// reserve the name first, as the type may refer to itself
+		s.defs[name] = &Schema{}
+		def := s.object(wire)
+		if wire.Kind() != reflect.Struct {
+			def = s.of(wire)
+		}
+		def.Title = name
+		s.defs[name] = def
+	}
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+// object describes a struct as encoded by encoding/json.
+func (s *schemas) object(t reflect.Type) *Schema {
+	obj := &Schema{Type: Types{"object"}, Properties: map[string]*Schema{}}
+	s.fields(t, obj)
+	return obj
+}
+
+func (s *schemas) fields(t reflect.Type, obj *Schema) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		ft := field.Type
+		if field.Anonymous && name == "" {
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				// promoted into the embedding struct
+				s.fields(ft, obj)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		schema := s.of(field.Type)
+		if strings.Contains(opts, "string") {
+			schema = &Schema{Type: Types{"string"}}
+		}
+		obj.Properties[name] = schema
+		if !strings.Contains(opts, "omitempty") {
+			obj.Required = append(obj.Required, name)
+		}
+	}
+}
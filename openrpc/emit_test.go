@@ -0,0 +1,49 @@
+package openrpc_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	client "github.com/celestiaorg/celestia-openrpc"
+	clientbuilder "github.com/celestiaorg/celestia-openrpc/builder"
+	"github.com/celestiaorg/celestia-openrpc/openrpc"
+)
+
+func TestEmit(t *testing.T) {
+	doc, err := openrpc.Emit(&client.Client{}, openrpc.EmitConfig{
+		Info: openrpc.Info{Title: "test", Version: "v1"},
+		Docs: map[string]string{"blob.Get": "Get retrieves the blob."},
+	})
+	require.NoError(t, err)
+
+	methods, err := clientbuilder.Methods(&client.Client{})
+	require.NoError(t, err)
+	require.Len(t, doc.Methods, len(methods))
+
+	// the document survives a round trip
+	raw, err := json.Marshal(doc)
+	require.NoError(t, err)
+	doc, err = openrpc.Read(bytes.NewReader(raw))
+	require.NoError(t, err)
+
+	var get openrpc.Method
+	for _, m := range doc.Methods {
+		if m.Name == "blob.Get" {
+			get = m
+		}
+	}
+	require.Equal(t, "read", get.Permission())
+	require.Equal(t, "Get retrieves the blob.", get.Doc())
+	require.Len(t, get.Params, 3)
+	require.Equal(t, "share.Namespace", get.Params[1].Type())
+	require.Equal(t, "base64", get.Params[1].Schema.ContentEncoding)
+	require.Equal(t, "*blob.Blob", get.Result.Type())
+
+	blob := doc.Components.Schemas[get.Result.Schema.RefName()]
+	require.NotNil(t, blob)
+	require.ElementsMatch(t, []string{"namespace", "data", "share_version", "commitment", "index"}, blob.Required)
+	require.True(t, blob.Properties["share_version"].Type.Is("integer"))
+}
@@ -0,0 +1,21 @@
+package openrpc
+
+import (
+	"reflect"
+
+	"github.com/celestiaorg/celestia-openrpc/types/blob"
+	"github.com/celestiaorg/celestia-openrpc/types/header"
+)
+
+// wireExtendedHeader is encoded like header.ExtendedHeader, which only changes
+// the encoding of some fields.
+type wireExtendedHeader header.ExtendedHeader
+
+// DefaultWireTypes returns the wire types of the types of this module that
+// have a custom JSON encoding.
+func DefaultWireTypes() map[reflect.Type]reflect.Type {
+	return map[reflect.Type]reflect.Type{
+		reflect.TypeOf(blob.Blob{}):             blob.WireType(),
+		reflect.TypeOf(header.ExtendedHeader{}): reflect.TypeOf(wireExtendedHeader{}),
+	}
+}
@@ -0,0 +1,70 @@
+package client
+
+import (
+	clientbuilder "github.com/celestiaorg/celestia-openrpc/builder"
+)
+
+// Option configures the connections the Client opens to the node.
+type Option = clientbuilder.Option
+
+// The options below are re-exported from the clientbuilder package, see there
+// for their documentation.
+var (
+	WithHTTPClient   = clientbuilder.WithHTTPClient
+	WithTLSConfig    = clientbuilder.WithTLSConfig
+	WithHeader       = clientbuilder.WithHeader
+	WithTimeout      = clientbuilder.WithTimeout
+	WithPingInterval = clientbuilder.WithPingInterval
+	WithReconnect    = clientbuilder.WithReconnect
+	WithNoReconnect  = clientbuilder.WithNoReconnect
+	WithDialer       = clientbuilder.WithDialer
+	WithLazyDial     = clientbuilder.WithLazyDial
+
+	WithPermissions        = clientbuilder.WithPermissions
+	WithoutPermissionCheck = clientbuilder.WithoutPermissionCheck
+
+	WithTokenProvider = clientbuilder.WithTokenProvider
+
+	WithRetry       = clientbuilder.WithRetry
+	WithModuleRetry = clientbuilder.WithModuleRetry
+	WithMethodRetry = clientbuilder.WithMethodRetry
+
+	WithInterceptors = clientbuilder.WithInterceptors
+
+	WithErrors = clientbuilder.WithErrors
+)
+
+// DialFunc opens connections to the node, see clientbuilder.WithDialer.
+type DialFunc = clientbuilder.DialFunc
+
+// TokenProvider supplies the token used to authenticate with the node.
+type TokenProvider = clientbuilder.TokenProvider
+
+// The token providers below are re-exported from the clientbuilder package.
+var (
+	StaticToken = clientbuilder.StaticToken
+	EnvToken    = clientbuilder.EnvToken
+	FileToken   = clientbuilder.FileToken
+	MintedToken = clientbuilder.MintedToken
+)
+
+// RetryPolicy configures how failed calls are retried.
+type RetryPolicy = clientbuilder.RetryPolicy
+
+// TransientError marks an error that may go away by trying again.
+type TransientError = clientbuilder.TransientError
+
+// The retry helpers below are re-exported from the clientbuilder package.
+var (
+	DefaultRetryPolicy = clientbuilder.DefaultRetryPolicy
+	IsTransient        = clientbuilder.IsTransient
+)
+
+// Interceptor runs around calls to the node, see clientbuilder.Interceptor.
+type Interceptor = clientbuilder.Interceptor
+
+// Call is a call to a method, as seen by interceptors.
+type Call = clientbuilder.Call
+
+// Invoker performs a call, filling in its outcome.
+type Invoker = clientbuilder.Invoker
@@ -0,0 +1,55 @@
+package client
+
+import (
+	"sync"
+
+	"github.com/filecoin-project/go-jsonrpc/auth"
+
+	clientbuilder "github.com/celestiaorg/celestia-openrpc/builder"
+)
+
+// ErrPermissionDenied is returned, wrapped in a *PermissionError, by calls to
+// methods that the client's token does not permit.
+var ErrPermissionDenied = clientbuilder.ErrPermissionDenied
+
+// PermissionError reports a call that was rejected on the client side.
+type PermissionError = clientbuilder.PermissionError
+
+var (
+	methodsOnce sync.Once
+	methods     map[string]clientbuilder.Method
+)
+
+// method looks up a method of the Client by its wire name, e.g. "blob.Submit".
+func method(name string) (clientbuilder.Method, bool) {
+	methodsOnce.Do(func() {
+		list, err := clientbuilder.Methods(&Client{})
+		if err != nil {
+			panic(err)
+		}
+		methods = make(map[string]clientbuilder.Method, len(list))
+		for _, m := range list {
+			methods[m.String()] = m
+		}
+	})
+	m, ok := methods[name]
+	return m, ok
+}
+
+// Permissions returns the permissions granted to the client's token, or nil
+// if they are unknown, e.g. because the token could not be decoded and the
+// node refused to verify it.
+func (c *Client) Permissions() []auth.Permission {
+	return c.perms
+}
+
+// Can reports whether the client is permitted to call the method with the
+// given name, e.g. "blob.Submit". If the permissions are unknown, Can reports
+// true for every method the Client declares, leaving the decision to the node.
+func (c *Client) Can(name string) bool {
+	m, ok := method(name)
+	if !ok {
+		return false
+	}
+	return c.perms == nil || c.perms.Allows(m)
+}
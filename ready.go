@@ -0,0 +1,259 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/celestiaorg/go-header/sync"
+
+	"github.com/celestiaorg/celestia-openrpc/types/das"
+)
+
+// ReadyStage is a stage of the node's readiness, in the order WaitReady waits
+// for them.
+type ReadyStage int
+
+const (
+	// ReadyRPC is the stage in which the node answers calls and Node.Ready
+	// reports true.
+	ReadyRPC ReadyStage = iota
+	// ReadyHeaderSync is the stage in which the node syncs headers up to the
+	// network head.
+	ReadyHeaderSync
+	// ReadyDAS is the stage in which the node samples the blocks it synced.
+	ReadyDAS
+	// ReadyDone is reported once the node is ready.
+	ReadyDone
+)
+
+func (s ReadyStage) String() string {
+	switch s {
+	case ReadyRPC:
+		return "rpc"
+	case ReadyHeaderSync:
+		return "header sync"
+	case ReadyDAS:
+		return "das"
+	case ReadyDone:
+		return "done"
+	default:
+		return fmt.Sprintf("ReadyStage(%d)", int(s))
+	}
+}
+
+// ReadyPolicy selects what WaitReady waits for. The zero value waits until the
+// RPC is up.
+type ReadyPolicy struct {
+	// HeaderSynced waits, once the RPC is up, until the local head is at most
+	// MaxHeaderLag heights behind the network head.
+	HeaderSynced bool
+	// MaxHeaderLag is how many heights the local head may be behind the
+	// network head. If zero, Header.SyncWait is waited for as well.
+	MaxHeaderLag uint64
+	// DASCaughtUp waits, once the previous stages are done, until sampling
+	// caught up with the head.
+	DASCaughtUp bool
+	// PollInterval is how often progress is checked. Defaults to 1 second.
+	PollInterval time.Duration
+	// OnProgress is called with the progress of every check. It is called
+	// synchronously and must not block.
+	OnProgress func(ReadyProgress)
+}
+
+// RPCUp is the policy waiting until the node answers calls and reports ready.
+func RPCUp() ReadyPolicy {
+	return ReadyPolicy{}
+}
+
+// HeaderSyncedWithin is the policy waiting until the node's local head is at
+// most maxLag heights behind the network head.
+func HeaderSyncedWithin(maxLag uint64) ReadyPolicy {
+	return ReadyPolicy{HeaderSynced: true, MaxHeaderLag: maxLag}
+}
+
+// DASCaughtUp is the policy waiting until the node synced all headers and
+// sampled all of their blocks.
+func DASCaughtUp() ReadyPolicy {
+	return ReadyPolicy{HeaderSynced: true, DASCaughtUp: true}
+}
+
+// ReadyProgress reports a check of the node's readiness.
+type ReadyProgress struct {
+	// Stage is the stage being waited for.
+	Stage ReadyStage
+	// SyncState is the state of header sync, once it was checked.
+	SyncState sync.State
+	// NetworkHeight is the height of the network head, once it was checked.
+	NetworkHeight uint64
+	// SamplingStats are the stats of sampling, once they were checked.
+	SamplingStats das.SamplingStats
+	// Err is the error of the check, if it failed.
+	Err error
+}
+
+// NotReadyError is returned by WaitReady when ctx is done before the node is
+// ready.
+type NotReadyError struct {
+	// Stage is the stage that stalled.
+	Stage ReadyStage
+	// Last is the progress of the last check.
+	Last ReadyProgress
+	// Err is the error of ctx.
+	Err error
+}
+
+func (e *NotReadyError) Error() string {
+	msg := fmt.Sprintf("client: node not ready: stalled at %s", e.Stage)
+	switch e.Stage {
+	case ReadyHeaderSync:
+		msg += fmt.Sprintf(" (local head %d, network head %d)", e.Last.SyncState.Height, e.Last.NetworkHeight)
+	case ReadyDAS:
+		msg += fmt.Sprintf(" (sampled %d of %d)", e.Last.SamplingStats.SampledChainHead, e.Last.SamplingStats.NetworkHead)
+	}
+	if e.Last.Err != nil {
+		msg += fmt.Sprintf(": %v", e.Last.Err)
+	}
+	return fmt.Sprintf("%s: %v", msg, e.Err)
+}
+
+func (e *NotReadyError) Unwrap() error {
+	return e.Err
+}
+
+// WaitReady waits until the node is ready as set by policy, checking the
+// stages in order: the RPC through Node.Ready, header sync through
+// Header.SyncState and Header.NetworkHead, and sampling through
+// DAS.SamplingStats. Failed checks are retried. If ctx is done first, a
+// *NotReadyError tells which stage stalled.
+func (c *Client) WaitReady(ctx context.Context, policy ReadyPolicy) error {
+	if policy.PollInterval == 0 {
+		policy.PollInterval = time.Second
+	}
+	if policy.OnProgress == nil {
+		policy.OnProgress = func(ReadyProgress) {}
+	}
+	w := &readyWaiter{c: c, policy: policy}
+
+	stages := []struct {
+		stage  ReadyStage
+		enable bool
+		check  func(context.Context) (bool, error)
+		wait   func(context.Context) error
+	}{
+		{ReadyRPC, true, w.checkRPC, nil},
+		{ReadyHeaderSync, policy.HeaderSynced, w.checkHeaderSync, w.headerSyncWait()},
+		{ReadyDAS, policy.DASCaughtUp, w.checkDAS, c.DAS.WaitCatchUp},
+	}
+	for _, s := range stages {
+		if !s.enable {
+			continue
+		}
+		w.progress.Stage = s.stage
+		if err := w.await(ctx, s.check, s.wait); err != nil {
+			return &NotReadyError{Stage: s.stage, Last: w.progress, Err: err}
+		}
+	}
+	w.progress.Stage = ReadyDone
+	w.progress.Err = nil
+	policy.OnProgress(w.progress)
+	return nil
+}
+
+type readyWaiter struct {
+	c        *Client
+	policy   ReadyPolicy
+	progress ReadyProgress
+}
+
+// await polls check until it reports true. If wait is set, it is called
+// alongside and its success ends the stage as well. It returns the error of
+// ctx if it is done first.
+func (w *readyWaiter) await(
+	ctx context.Context,
+	check func(context.Context) (bool, error),
+	wait func(context.Context) error,
+) error {
+	var waited chan error
+	if wait != nil {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		waited = make(chan error, 1)
+		go func() {
+			waited <- wait(ctx)
+		}()
+	}
+
+	ticker := time.NewTicker(w.policy.PollInterval)
+	defer ticker.Stop()
+	for {
+		done, err := check(ctx)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		w.progress.Err = err
+		w.policy.OnProgress(w.progress)
+		if done {
+			return nil
+		}
+
+		select {
+		case <-ticker.C:
+		case err := <-waited:
+			if err == nil {
+				return nil
+			}
+			// the node may not support waiting, so keep polling
+			waited = nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (w *readyWaiter) checkRPC(ctx context.Context) (bool, error) {
+	ready, err := w.c.Node.Ready(ctx)
+	if err != nil {
+		return false, err
+	}
+	if !ready {
+		return false, errors.New("node is not ready")
+	}
+	return true, nil
+}
+
+func (w *readyWaiter) checkHeaderSync(ctx context.Context) (bool, error) {
+	state, err := w.c.Header.SyncState(ctx)
+	if err != nil {
+		return false, err
+	}
+	w.progress.SyncState = state
+	head, err := w.c.Header.NetworkHead(ctx)
+	if err != nil {
+		return false, err
+	}
+	w.progress.NetworkHeight = head.Height()
+	if w.policy.MaxHeaderLag == 0 && !state.Finished() {
+		return false, nil
+	}
+	return state.Height+w.policy.MaxHeaderLag >= head.Height(), nil
+}
+
+// headerSyncWait returns Header.SyncWait if the policy allows no lag, as it
+// only returns once sync is finished.
+func (w *readyWaiter) headerSyncWait() func(context.Context) error {
+	if w.policy.MaxHeaderLag != 0 {
+		return nil
+	}
+	return w.c.Header.SyncWait
+}
+
+func (w *readyWaiter) checkDAS(ctx context.Context) (bool, error) {
+	stats, err := w.c.DAS.SamplingStats(ctx)
+	if err != nil {
+		return false, err
+	}
+	w.progress.SamplingStats = stats
+	return stats.CatchUpDone, nil
+}
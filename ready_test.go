@@ -0,0 +1,69 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/celestiaorg/go-header/sync"
+	"github.com/stretchr/testify/require"
+
+	"github.com/celestiaorg/celestia-openrpc/types/das"
+	"github.com/celestiaorg/celestia-openrpc/types/header"
+	"github.com/celestiaorg/celestia-openrpc/types/node"
+)
+
+// TestWaitReady_Stalled ensures that the stage that stalls is reported, and
+// that failed checks are retried.
+func TestWaitReady_Stalled(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	var readyCalls int
+	c := &Client{
+		Node: node.API{
+			Ready: func(context.Context) (bool, error) {
+				readyCalls++
+				if readyCalls == 1 {
+					return false, errors.New("connection refused")
+				}
+				return true, nil
+			},
+		},
+		Header: header.API{
+			SyncState: func(context.Context) (sync.State, error) {
+				return sync.State{Height: 90, ToHeight: 100}, nil
+			},
+			NetworkHead: func(context.Context) (*header.ExtendedHeader, error) {
+				return testHeader(100), nil
+			},
+			SyncWait: func(ctx context.Context) error {
+				<-ctx.Done()
+				return ctx.Err()
+			},
+		},
+		DAS: das.API{
+			SamplingStats: func(context.Context) (das.SamplingStats, error) {
+				return das.SamplingStats{CatchUpDone: true}, nil
+			},
+			WaitCatchUp: func(context.Context) error {
+				return nil
+			},
+		},
+	}
+
+	policy := ReadyPolicy{HeaderSynced: true, MaxHeaderLag: 10, PollInterval: 10 * time.Millisecond}
+	require.NoError(t, c.WaitReady(ctx, policy))
+	require.Equal(t, 2, readyCalls)
+
+	policy.MaxHeaderLag = 5
+	err := c.WaitReady(ctx, policy)
+	var notReady *NotReadyError
+	require.ErrorAs(t, err, &notReady)
+	require.Equal(t, ReadyHeaderSync, notReady.Stage)
+	require.EqualValues(t, 90, notReady.Last.SyncState.Height)
+	require.EqualValues(t, 100, notReady.Last.NetworkHeight)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.ErrorContains(t, err, "stalled at header sync (local head 90, network head 100)")
+}
@@ -0,0 +1,9 @@
+// Package rpcreplay records the JSON-RPC traffic between a client and a node
+// to a fixture file, and replays it to clients, so that tests run against
+// real wire formats without a node.
+//
+// A Recorder is a proxy recording requests, responses and the values sent
+// over subscriptions, with tokens and other secrets redacted. A Replayer
+// serves the recorded responses to the requests matching the recorded ones,
+// optionally regardless of IDs and heights.
+package rpcreplay
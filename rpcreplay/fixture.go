@@ -0,0 +1,49 @@
+package rpcreplay
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// FixtureVersion is the version of the fixture format written by Recorder.
+const FixtureVersion = 1
+
+// Fixture is the JSON-RPC traffic of a recorded session.
+type Fixture struct {
+	Version      int           `json:"version"`
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a request and the frames the node sent in reply: its
+// response and, for subscriptions, the values sent over the channel. A batch
+// request and its response are recorded as JSON arrays.
+type Interaction struct {
+	Request   json.RawMessage   `json:"request"`
+	Responses []json.RawMessage `json:"responses"`
+}
+
+// Load reads the fixture at path.
+func Load(path string) (*Fixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var f Fixture
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("rpcreplay: decoding %s: %w", path, err)
+	}
+	if f.Version != FixtureVersion {
+		return nil, fmt.Errorf("rpcreplay: unsupported fixture version %d in %s", f.Version, path)
+	}
+	return &f, nil
+}
+
+// Save writes the fixture to path.
+func (f *Fixture) Save(path string) error {
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
@@ -0,0 +1,100 @@
+package rpcreplay
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+const (
+	chanValue = "xrpc.ch.val"
+	chanClose = "xrpc.ch.close"
+)
+
+// frame holds the fields of a JSON-RPC message that recording and replay
+// look at.
+type frame struct {
+	ID     json.RawMessage `json:"id,omitempty"`
+	Method string          `json:"method,omitempty"`
+	Params json.RawMessage `json:"params,omitempty"`
+	Result json.RawMessage `json:"result,omitempty"`
+}
+
+// isCall reports whether the frame is a request expecting a response, as
+// opposed to a notification such as the cancellation of a request.
+func (f frame) isCall() bool {
+	return len(f.ID) > 0 && !strings.HasPrefix(f.Method, "xrpc.")
+}
+
+// isResponse reports whether the frame is a response to a request.
+func (f frame) isResponse() bool {
+	return len(f.ID) > 0 && f.Method == ""
+}
+
+// isChannel reports whether the frame carries a value sent over a channel
+// or its closing.
+func (f frame) isChannel() bool {
+	return f.Method == chanValue || f.Method == chanClose
+}
+
+// channel returns the ID of the channel a channel frame is for.
+func (f frame) channel() string {
+	var params []json.RawMessage
+	if err := json.Unmarshal(f.Params, &params); err != nil || len(params) == 0 {
+		return ""
+	}
+	return key(params[0])
+}
+
+// key returns the canonical form of an ID to index by.
+func key(id json.RawMessage) string {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, id); err != nil {
+		return string(id)
+	}
+	return buf.String()
+}
+
+// split returns the messages of a frame or batch of frames.
+func split(data []byte) ([]json.RawMessage, bool, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '[' {
+		var elems []json.RawMessage
+		err := json.Unmarshal(data, &elems)
+		return elems, true, err
+	}
+	return []json.RawMessage{data}, false, nil
+}
+
+// join is the inverse of split.
+func join(elems []json.RawMessage, batch bool) (json.RawMessage, error) {
+	if !batch {
+		return elems[0], nil
+	}
+	return json.Marshal(elems)
+}
+
+// parse decodes the messages of a frame or batch of frames.
+func parse(data []byte) ([]json.RawMessage, []frame, bool, error) {
+	elems, batch, err := split(data)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	frames := make([]frame, len(elems))
+	for i, elem := range elems {
+		if err := json.Unmarshal(elem, &frames[i]); err != nil {
+			return nil, nil, false, err
+		}
+	}
+	return elems, frames, batch, nil
+}
+
+// set returns msg with its field set to value.
+func set(msg json.RawMessage, field string, value json.RawMessage) (json.RawMessage, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(msg, &fields); err != nil {
+		return nil, err
+	}
+	fields[field] = value
+	return json.Marshal(fields)
+}
@@ -0,0 +1,102 @@
+package rpcreplay
+
+// Redacted replaces secrets in fixtures. A recorded param that is exactly
+// Redacted matches any value on replay.
+const Redacted = "[REDACTED]"
+
+// Option configures a Recorder or a Replayer.
+type Option func(*config)
+
+type config struct {
+	// recording
+	redactMethods map[string]bool
+	secrets       []string
+	subscriptions map[string]bool
+
+	// replay
+	ignoreIDs        bool
+	normalizeHeights bool
+	heightParams     map[string][]int
+}
+
+func defaultConfig() *config {
+	return &config{
+		// tokens are passed to and returned from these
+		redactMethods: map[string]bool{
+			"node.AuthVerify": true,
+			"node.AuthNew":    true,
+		},
+		subscriptions: map[string]bool{
+			"header.Subscribe": true,
+			"fraud.Subscribe":  true,
+		},
+		heightParams: map[string][]int{
+			"blob.Get":                {0},
+			"blob.GetAll":             {0},
+			"blob.GetProof":           {0},
+			"blob.Included":           {0},
+			"da.GetIDs":               {0},
+			"header.GetByHeight":      {0},
+			"header.WaitForHeight":    {0},
+			"header.GetRangeByHeight": {1},
+		},
+	}
+}
+
+// RedactMethods records the params and results of the methods as Redacted,
+// in addition to node.AuthVerify and node.AuthNew. Applies to a Recorder.
+func RedactMethods(methods ...string) Option {
+	return func(cfg *config) {
+		for _, m := range methods {
+			cfg.redactMethods[m] = true
+		}
+	}
+}
+
+// RedactSecrets replaces every occurrence of the secrets in recorded frames,
+// such as tokens or addresses, with Redacted. Applies to a Recorder.
+func RedactSecrets(secrets ...string) Option {
+	return func(cfg *config) {
+		for _, s := range secrets {
+			if s != "" {
+				cfg.secrets = append(cfg.secrets, s)
+			}
+		}
+	}
+}
+
+// WithSubscriptions records the values sent over the channels returned by
+// the methods, in addition to header.Subscribe and fraud.Subscribe. Applies
+// to a Recorder.
+func WithSubscriptions(methods ...string) Option {
+	return func(cfg *config) {
+		for _, m := range methods {
+			cfg.subscriptions[m] = true
+		}
+	}
+}
+
+// IgnoreIDs matches requests regardless of their JSON-RPC IDs, which depend
+// on the order calls are made in. Applies to a Replayer.
+func IgnoreIDs() Option {
+	return func(cfg *config) {
+		cfg.ignoreIDs = true
+	}
+}
+
+// NormalizeHeights matches the heights passed to methods by their offset from
+// the first height requested, so that a fixture recorded at one height
+// replays for requests starting at another. Applies to a Replayer.
+func NormalizeHeights() Option {
+	return func(cfg *config) {
+		cfg.normalizeHeights = true
+	}
+}
+
+// WithHeightParam declares that the param at position of method is a height
+// for NormalizeHeights. Applies to a Replayer.
+func WithHeightParam(method string, position int) Option {
+	return func(cfg *config) {
+		cfg.heightParams[method] = append(cfg.heightParams[method], position)
+	}
+}
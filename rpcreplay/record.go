@@ -0,0 +1,359 @@
+package rpcreplay
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/gorilla/websocket"
+)
+
+// Recorder is a proxy between a client and a node recording the traffic
+// between them:
+//
+//	rec, err := rpcreplay.NewRecorder("ws://localhost:26658")
+//	if err != nil {
+//		return err
+//	}
+//	defer rec.Close()
+//
+//	c, err := client.NewClient(ctx, rec.URL(), token)
+//	// ... use c
+//	err = rec.Fixture().Save("testdata/session.json")
+//
+// Headers, including the Authorization header, are forwarded to the node but
+// not recorded.
+type Recorder struct {
+	cfg    *config
+	target *url.URL
+	srv    *httptest.Server
+
+	mu           sync.Mutex
+	interactions []*Interaction
+}
+
+// NewRecorder starts a proxy to the node at addr, which is either an HTTP or
+// a websocket address.
+func NewRecorder(addr string, opts ...Option) (*Recorder, error) {
+	target, err := url.Parse(addr)
+	if err != nil {
+		return nil, fmt.Errorf("rpcreplay: parsing node address: %w", err)
+	}
+	switch target.Scheme {
+	case "http", "https", "ws", "wss":
+	default:
+		return nil, fmt.Errorf("rpcreplay: unsupported scheme %q", target.Scheme)
+	}
+
+	cfg := defaultConfig()
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	r := &Recorder{cfg: cfg, target: target}
+	r.srv = httptest.NewServer(http.HandlerFunc(r.serve))
+	return r, nil
+}
+
+// URL returns the address to connect the client to, with the scheme of the
+// node's address.
+func (r *Recorder) URL() string {
+	if r.websocket() {
+		return "ws" + strings.TrimPrefix(r.srv.URL, "http")
+	}
+	return r.srv.URL
+}
+
+// Fixture returns the traffic recorded so far, ordered by request.
+func (r *Recorder) Fixture() *Fixture {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	f := &Fixture{Version: FixtureVersion, Interactions: make([]Interaction, len(r.interactions))}
+	for i, in := range r.interactions {
+		f.Interactions[i] = Interaction{
+			Request:   in.Request,
+			Responses: append([]json.RawMessage(nil), in.Responses...),
+		}
+	}
+	return f
+}
+
+// Close shuts the proxy down, closing the connections to the node.
+func (r *Recorder) Close() {
+	r.srv.CloseClientConnections()
+	r.srv.Close()
+}
+
+func (r *Recorder) websocket() bool {
+	return r.target.Scheme == "ws" || r.target.Scheme == "wss"
+}
+
+// targetURL returns the node's address with the scheme for ws or HTTP.
+func (r *Recorder) targetURL(ws bool) string {
+	u := *r.target
+	secure := u.Scheme == "https" || u.Scheme == "wss"
+	switch {
+	case ws && secure:
+		u.Scheme = "wss"
+	case ws:
+		u.Scheme = "ws"
+	case secure:
+		u.Scheme = "https"
+	default:
+		u.Scheme = "http"
+	}
+	return u.String()
+}
+
+func (r *Recorder) serve(w http.ResponseWriter, req *http.Request) {
+	if websocket.IsWebSocketUpgrade(req) {
+		r.serveWS(w, req)
+		return
+	}
+	r.serveHTTP(w, req)
+}
+
+func (r *Recorder) serveHTTP(w http.ResponseWriter, req *http.Request) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	out, err := http.NewRequestWithContext(req.Context(), req.Method, r.targetURL(false), bytes.NewReader(body))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	out.Header = req.Header.Clone()
+	// record the response as sent, not compressed
+	out.Header.Del("Accept-Encoding")
+
+	resp, err := http.DefaultClient.Do(out)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	if resp.StatusCode == http.StatusOK {
+		r.recordExchange(body, respBody)
+	}
+
+	w.Header().Set("Content-Type", resp.Header.Get("Content-Type"))
+	w.WriteHeader(resp.StatusCode)
+	_, _ = w.Write(respBody)
+}
+
+// recordExchange records an HTTP request, single or batch, and its response.
+func (r *Recorder) recordExchange(req, resp []byte) {
+	reqElems, reqFrames, batch, err := parse(req)
+	if err != nil {
+		return
+	}
+	methods := map[string]string{}
+	for i, f := range reqFrames {
+		methods[key(f.ID)] = f.Method
+		reqElems[i] = r.cfg.redact(reqElems[i], f.Method)
+	}
+	respElems, respFrames, _, err := parse(resp)
+	if err != nil {
+		return
+	}
+	for i, f := range respFrames {
+		respElems[i] = r.cfg.redact(respElems[i], methods[key(f.ID)])
+	}
+
+	in := &Interaction{}
+	if in.Request, err = join(reqElems, batch); err != nil {
+		return
+	}
+	respFrame, err := join(respElems, batch)
+	if err != nil {
+		return
+	}
+	in.Responses = []json.RawMessage{respFrame}
+	r.mu.Lock()
+	r.interactions = append(r.interactions, in)
+	r.mu.Unlock()
+}
+
+// wsHeaders are set by the websocket dialer and must not be forwarded.
+var wsHeaders = []string{
+	"Upgrade",
+	"Connection",
+	"Sec-Websocket-Key",
+	"Sec-Websocket-Version",
+	"Sec-Websocket-Extensions",
+	"Sec-Websocket-Protocol",
+}
+
+func (r *Recorder) serveWS(w http.ResponseWriter, req *http.Request) {
+	header := req.Header.Clone()
+	for _, h := range wsHeaders {
+		header.Del(h)
+	}
+	nodeConn, resp, err := websocket.DefaultDialer.DialContext(req.Context(), r.targetURL(true), header)
+	if err != nil {
+		status := http.StatusBadGateway
+		if resp != nil {
+			status = resp.StatusCode
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+	defer nodeConn.Close()
+
+	upgrader := websocket.Upgrader{CheckOrigin: func(*http.Request) bool { return true }}
+	clientConn, err := upgrader.Upgrade(w, req, nil)
+	if err != nil {
+		return
+	}
+	defer clientConn.Close()
+
+	s := &session{
+		r:       r,
+		pending: map[string]pendingCall{},
+		chans:   map[string]pendingCall{},
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		pipe(nodeConn, clientConn, s.fromNode)
+		clientConn.Close()
+	}()
+	pipe(clientConn, nodeConn, s.fromClient)
+	nodeConn.Close()
+	<-done
+}
+
+// pipe copies the messages from src to dst until either fails, passing each
+// to record first.
+func pipe(src, dst *websocket.Conn, record func([]byte)) {
+	for {
+		typ, data, err := src.ReadMessage()
+		if err != nil {
+			return
+		}
+		record(data)
+		if err := dst.WriteMessage(typ, data); err != nil {
+			return
+		}
+	}
+}
+
+// pendingCall is a request recorded on a websocket connection.
+type pendingCall struct {
+	in     *Interaction
+	method string
+}
+
+// session records the traffic of a websocket connection.
+type session struct {
+	r *Recorder
+
+	mu sync.Mutex
+	// pending are the requests awaiting a response, by ID.
+	pending map[string]pendingCall
+	// chans are the subscriptions, by channel ID.
+	chans map[string]pendingCall
+}
+
+func (s *session) fromClient(data []byte) {
+	var f frame
+	if err := json.Unmarshal(data, &f); err != nil || !f.isCall() {
+		return
+	}
+	call := pendingCall{
+		in:     &Interaction{Request: s.r.cfg.redact(data, f.Method)},
+		method: f.Method,
+	}
+	s.mu.Lock()
+	s.pending[key(f.ID)] = call
+	s.mu.Unlock()
+
+	s.r.mu.Lock()
+	s.r.interactions = append(s.r.interactions, call.in)
+	s.r.mu.Unlock()
+}
+
+func (s *session) fromNode(data []byte) {
+	var f frame
+	if err := json.Unmarshal(data, &f); err != nil {
+		return
+	}
+
+	s.mu.Lock()
+	var (
+		call pendingCall
+		ok   bool
+	)
+	switch {
+	case f.isChannel():
+		call, ok = s.chans[f.channel()]
+		if f.Method == chanClose {
+			delete(s.chans, f.channel())
+		}
+	case f.isResponse():
+		call, ok = s.pending[key(f.ID)]
+		delete(s.pending, key(f.ID))
+		if ok && s.r.cfg.subscriptions[call.method] && len(f.Result) > 0 {
+			s.chans[key(f.Result)] = call
+		}
+	}
+	s.mu.Unlock()
+	if !ok {
+		return
+	}
+
+	s.r.mu.Lock()
+	call.in.Responses = append(call.in.Responses, s.r.cfg.redact(data, call.method))
+	s.r.mu.Unlock()
+}
+
+// redact removes the secrets from msg, a message of a call to method.
+func (c *config) redact(msg json.RawMessage, method string) json.RawMessage {
+	if c.redactMethods[method] {
+		msg = redactFields(msg)
+	}
+	for _, secret := range c.secrets {
+		msg = bytes.ReplaceAll(msg, []byte(secret), []byte(Redacted))
+	}
+	return msg
+}
+
+// redactFields replaces each param and the result of msg with Redacted.
+func redactFields(msg json.RawMessage) json.RawMessage {
+	redacted, _ := json.Marshal(Redacted)
+	var f frame
+	if err := json.Unmarshal(msg, &f); err != nil {
+		return msg
+	}
+	if len(f.Params) > 0 {
+		var params []json.RawMessage
+		if err := json.Unmarshal(f.Params, &params); err != nil {
+			return msg
+		}
+		for i := range params {
+			params[i] = redacted
+		}
+		encoded, _ := json.Marshal(params)
+		if out, err := set(msg, "params", encoded); err == nil {
+			msg = out
+		}
+	}
+	if len(f.Result) > 0 && !bytes.Equal(f.Result, []byte("null")) {
+		if out, err := set(msg, "result", redacted); err == nil {
+			msg = out
+		}
+	}
+	return msg
+}
@@ -0,0 +1,377 @@
+package rpcreplay
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gorilla/websocket"
+)
+
+// errNoMatch is the JSON-RPC error code of requests matching no interaction.
+const errNoMatch = -32000
+
+// Replayer serves a Fixture to clients, answering each request with the
+// responses recorded for it:
+//
+//	fixture, err := rpcreplay.Load("testdata/session.json")
+//	if err != nil {
+//		return err
+//	}
+//	rp, err := rpcreplay.NewReplayer(fixture, rpcreplay.IgnoreIDs())
+//	if err != nil {
+//		return err
+//	}
+//	defer rp.Close()
+//
+//	c, err := client.NewClient(ctx, rp.URL(), "")
+//
+// A request matches an interaction with the same method, params and, unless
+// IgnoreIDs is set, ID. Each interaction is replayed once, the earliest
+// recorded first. Requests matching none are answered with an error and
+// reported by Unmatched.
+type Replayer struct {
+	cfg *config
+	srv *httptest.Server
+
+	mu           sync.Mutex
+	interactions []*replayed
+	// recordedBase and requestedBase are the first heights recorded and
+	// requested, from which NormalizeHeights computes offsets.
+	recordedBase  *uint64
+	requestedBase *uint64
+	unmatched     []string
+}
+
+// replayed is an interaction with its request decoded.
+type replayed struct {
+	Interaction
+	calls []call
+	used  bool
+}
+
+// call is a decoded request.
+type call struct {
+	id     json.RawMessage
+	method string
+	params []interface{}
+}
+
+// NewReplayer starts a server replaying the fixture.
+func NewReplayer(f *Fixture, opts ...Option) (*Replayer, error) {
+	cfg := defaultConfig()
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	r := &Replayer{cfg: cfg}
+	for i, in := range f.Interactions {
+		calls, err := decodeCalls(in.Request)
+		if err != nil {
+			return nil, fmt.Errorf("rpcreplay: decoding request of interaction %d: %w", i, err)
+		}
+		r.interactions = append(r.interactions, &replayed{Interaction: in, calls: calls})
+		if r.recordedBase == nil {
+			r.recordedBase = r.firstHeight(calls)
+		}
+	}
+	r.srv = httptest.NewServer(http.HandlerFunc(r.serve))
+	return r, nil
+}
+
+// URL returns the websocket address of the server.
+func (r *Replayer) URL() string {
+	return "ws" + strings.TrimPrefix(r.srv.URL, "http")
+}
+
+// HTTPURL returns the HTTP address of the server.
+func (r *Replayer) HTTPURL() string {
+	return r.srv.URL
+}
+
+// Unmatched returns the requests that matched no interaction, formatted as
+// method(params).
+func (r *Replayer) Unmatched() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.unmatched...)
+}
+
+// Close shuts the server down.
+func (r *Replayer) Close() {
+	r.srv.CloseClientConnections()
+	r.srv.Close()
+}
+
+func (r *Replayer) serve(w http.ResponseWriter, req *http.Request) {
+	if websocket.IsWebSocketUpgrade(req) {
+		r.serveWS(w, req)
+		return
+	}
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	frames, err := r.reply(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if len(frames) > 0 {
+		_, _ = w.Write(frames[0])
+	}
+}
+
+func (r *Replayer) serveWS(w http.ResponseWriter, req *http.Request) {
+	upgrader := websocket.Upgrader{CheckOrigin: func(*http.Request) bool { return true }}
+	conn, err := upgrader.Upgrade(w, req, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	for {
+		typ, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		frames, err := r.reply(data)
+		if err != nil {
+			return
+		}
+		for _, f := range frames {
+			if err := conn.WriteMessage(typ, f); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// reply returns the frames to send in reply to a request, single or batch.
+// Notifications get no reply.
+func (r *Replayer) reply(data []byte) ([]json.RawMessage, error) {
+	calls, err := decodeCalls(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(calls) == 0 {
+		return nil, nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.requestedBase == nil {
+		r.requestedBase = r.firstHeight(calls)
+	}
+	for _, in := range r.interactions {
+		if in.used || !r.matches(in.calls, calls) {
+			continue
+		}
+		in.used = true
+		ids := make(map[string]json.RawMessage, len(calls))
+		for i, c := range in.calls {
+			ids[key(c.id)] = calls[i].id
+		}
+		frames := make([]json.RawMessage, 0, len(in.Responses))
+		for _, resp := range in.Responses {
+			f, err := rewriteIDs(resp, ids)
+			if err != nil {
+				return nil, err
+			}
+			frames = append(frames, f)
+		}
+		return frames, nil
+	}
+
+	errs := make([]json.RawMessage, len(calls))
+	for i, c := range calls {
+		params, _ := json.Marshal(c.params)
+		desc := fmt.Sprintf("%s(%s)", c.method, bytes.Trim(params, "[]"))
+		r.unmatched = append(r.unmatched, desc)
+		errs[i], err = json.Marshal(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      c.id,
+			"error": map[string]interface{}{
+				"code":    errNoMatch,
+				"message": "rpcreplay: no recorded interaction matches " + desc,
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	_, batch, _ := split(data)
+	f, err := join(errs, batch)
+	if err != nil {
+		return nil, err
+	}
+	return []json.RawMessage{f}, nil
+}
+
+// matches reports whether the requested calls match the recorded ones.
+func (r *Replayer) matches(recorded, requested []call) bool {
+	if len(recorded) != len(requested) {
+		return false
+	}
+	for i, rec := range recorded {
+		req := requested[i]
+		if rec.method != req.method || len(rec.params) != len(req.params) {
+			return false
+		}
+		if !r.cfg.ignoreIDs && key(rec.id) != key(req.id) {
+			return false
+		}
+		heights := r.heightPositions(rec.method)
+		for pos, param := range rec.params {
+			if heights[pos] {
+				if !r.sameOffset(param, req.params[pos]) {
+					return false
+				}
+				continue
+			}
+			if !equal(param, req.params[pos]) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// heightPositions returns the positions of the heights among the params of
+// method if heights are normalized.
+func (r *Replayer) heightPositions(method string) map[int]bool {
+	if !r.cfg.normalizeHeights {
+		return nil
+	}
+	positions := map[int]bool{}
+	for _, pos := range r.cfg.heightParams[method] {
+		positions[pos] = true
+	}
+	return positions
+}
+
+// sameOffset reports whether the recorded and requested heights are as far
+// from the first recorded and requested heights respectively.
+func (r *Replayer) sameOffset(recorded, requested interface{}) bool {
+	rec, ok1 := height(recorded)
+	req, ok2 := height(requested)
+	if !ok1 || !ok2 || r.recordedBase == nil || r.requestedBase == nil {
+		return equal(recorded, requested)
+	}
+	return rec-*r.recordedBase == req-*r.requestedBase
+}
+
+// firstHeight returns the first height among the params of calls, if any.
+func (r *Replayer) firstHeight(calls []call) *uint64 {
+	for _, c := range calls {
+		for _, pos := range r.cfg.heightParams[c.method] {
+			if pos >= len(c.params) {
+				continue
+			}
+			if h, ok := height(c.params[pos]); ok {
+				return &h
+			}
+		}
+	}
+	return nil
+}
+
+func height(param interface{}) (uint64, bool) {
+	n, ok := param.(json.Number)
+	if !ok {
+		return 0, false
+	}
+	h, err := strconv.ParseUint(n.String(), 10, 64)
+	return h, err == nil
+}
+
+// decodeCalls decodes the calls of a request, single or batch, skipping
+// notifications.
+func decodeCalls(data []byte) ([]call, error) {
+	_, frames, _, err := parse(data)
+	if err != nil {
+		return nil, err
+	}
+	var calls []call
+	for _, f := range frames {
+		if !f.isCall() {
+			continue
+		}
+		c := call{id: f.ID, method: f.Method}
+		if len(f.Params) > 0 {
+			dec := json.NewDecoder(bytes.NewReader(f.Params))
+			dec.UseNumber()
+			if err := dec.Decode(&c.params); err != nil {
+				return nil, err
+			}
+		}
+		calls = append(calls, c)
+	}
+	return calls, nil
+}
+
+// equal reports whether two decoded JSON values are equal, a Redacted
+// recorded value being equal to any.
+func equal(recorded, requested interface{}) bool {
+	switch rec := recorded.(type) {
+	case string:
+		if rec == Redacted {
+			return true
+		}
+		req, ok := requested.(string)
+		return ok && rec == req
+	case []interface{}:
+		req, ok := requested.([]interface{})
+		if !ok || len(rec) != len(req) {
+			return false
+		}
+		for i := range rec {
+			if !equal(rec[i], req[i]) {
+				return false
+			}
+		}
+		return true
+	case map[string]interface{}:
+		req, ok := requested.(map[string]interface{})
+		if !ok || len(rec) != len(req) {
+			return false
+		}
+		for k, v := range rec {
+			w, ok := req[k]
+			if !ok || !equal(v, w) {
+				return false
+			}
+		}
+		return true
+	default:
+		return recorded == requested
+	}
+}
+
+// rewriteIDs replaces the recorded IDs of the responses in a frame with the
+// requested ones.
+func rewriteIDs(data json.RawMessage, ids map[string]json.RawMessage) (json.RawMessage, error) {
+	elems, frames, batch, err := parse(data)
+	if err != nil {
+		return nil, err
+	}
+	for i, f := range frames {
+		if !f.isResponse() {
+			continue
+		}
+		id, ok := ids[key(f.ID)]
+		if !ok {
+			continue
+		}
+		if elems[i], err = set(elems[i], "id", id); err != nil {
+			return nil, err
+		}
+	}
+	return join(elems, batch)
+}
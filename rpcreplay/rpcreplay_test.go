@@ -0,0 +1,129 @@
+package rpcreplay_test
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"cosmossdk.io/math"
+	"github.com/stretchr/testify/require"
+
+	client "github.com/celestiaorg/celestia-openrpc"
+	"github.com/celestiaorg/celestia-openrpc/rpcreplay"
+	"github.com/celestiaorg/celestia-openrpc/testnode"
+	"github.com/celestiaorg/celestia-openrpc/types/blob"
+	"github.com/celestiaorg/celestia-openrpc/types/share"
+	"github.com/celestiaorg/celestia-openrpc/types/state"
+)
+
+const token = "secret-token"
+
+func dial(t *testing.T, addr, token string) *client.Client {
+	t.Helper()
+	// the connection lives as long as the context it is dialed with
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	c, err := client.NewClient(ctx, addr, token)
+	require.NoError(t, err)
+	t.Cleanup(c.Close)
+	return c
+}
+
+func TestRecordReplay(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	node, err := testnode.New(testnode.WithBlockTime(10 * time.Millisecond))
+	require.NoError(t, err)
+	rec, err := rpcreplay.NewRecorder(node.URL())
+	require.NoError(t, err)
+	defer rec.Close()
+
+	ns, err := share.NewBlobNamespaceV0([]byte("rpcreplay"))
+	require.NoError(t, err)
+	b, err := blob.NewBlobV0(ns, []byte("hello"))
+	require.NoError(t, err)
+
+	// the token is not a JWT, so its permissions are asked to the node
+	c := dial(t, rec.URL(), token)
+	sub, err := c.Header.Subscribe(ctx)
+	require.NoError(t, err)
+	recorded := []uint64{(<-sub).Height(), (<-sub).Height()}
+	height, err := c.Blob.Submit(ctx, []*blob.Blob{b}, blob.DefaultGasPrice())
+	require.NoError(t, err)
+	got, err := c.Blob.Get(ctx, height, ns, b.Commitment)
+	require.NoError(t, err)
+	resp, err := c.State.Transfer(ctx, state.AccAddress("recipient"), math.NewInt(300), math.NewInt(100), 100_000)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "session.json")
+	require.NoError(t, rec.Fixture().Save(path))
+	node.Close()
+
+	fixture, err := rpcreplay.Load(path)
+	require.NoError(t, err)
+	for _, in := range fixture.Interactions {
+		require.NotContains(t, string(in.Request), token)
+	}
+
+	rp, err := rpcreplay.NewReplayer(fixture, rpcreplay.IgnoreIDs())
+	require.NoError(t, err)
+	defer rp.Close()
+	c = dial(t, rp.URL(), "")
+
+	sub, err = c.Header.Subscribe(ctx)
+	require.NoError(t, err)
+	require.Equal(t, recorded, []uint64{(<-sub).Height(), (<-sub).Height()})
+	replayedHeight, err := c.Blob.Submit(ctx, []*blob.Blob{b}, blob.DefaultGasPrice())
+	require.NoError(t, err)
+	require.Equal(t, height, replayedHeight)
+	replayed, err := c.Blob.Get(ctx, height, ns, b.Commitment)
+	require.NoError(t, err)
+	require.Equal(t, got.Data, replayed.Data)
+	require.Equal(t, got.Index(), replayed.Index())
+	replayedResp, err := c.State.Transfer(ctx, state.AccAddress("recipient"), math.NewInt(300), math.NewInt(100), 100_000)
+	require.NoError(t, err)
+	require.Equal(t, resp, replayedResp)
+
+	_, err = c.Blob.Get(ctx, height+1, ns, b.Commitment)
+	require.ErrorContains(t, err, "no recorded interaction")
+	require.Len(t, rp.Unmatched(), 1)
+	require.True(t, strings.HasPrefix(rp.Unmatched()[0], "blob.Get("))
+}
+
+func TestReplay_NormalizeHeights(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	node, err := testnode.New()
+	require.NoError(t, err)
+	defer node.Close()
+	rec, err := rpcreplay.NewRecorder("http" + strings.TrimPrefix(node.URL(), "ws"))
+	require.NoError(t, err)
+	defer rec.Close()
+
+	c := dial(t, rec.URL(), "")
+	for height := uint64(1); height <= 2; height++ {
+		_, err = node.ProduceBlock()
+		require.NoError(t, err)
+		_, err := c.Header.GetByHeight(ctx, height)
+		require.NoError(t, err)
+	}
+
+	rp, err := rpcreplay.NewReplayer(rec.Fixture(), rpcreplay.NormalizeHeights())
+	require.NoError(t, err)
+	defer rp.Close()
+	c = dial(t, rp.HTTPURL(), "")
+
+	// the same calls made 100 blocks later
+	h, err := c.Header.GetByHeight(ctx, 101)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, h.Height())
+	h, err = c.Header.GetByHeight(ctx, 102)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, h.Height())
+	_, err = c.Header.GetByHeight(ctx, 102)
+	require.ErrorContains(t, err, "no recorded interaction")
+}
@@ -0,0 +1,341 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	gofraud "github.com/celestiaorg/go-fraud"
+
+	"github.com/celestiaorg/celestia-openrpc/types/fraud"
+	"github.com/celestiaorg/celestia-openrpc/types/header"
+)
+
+// SubscriptionEventType describes what happened to a resilient subscription.
+type SubscriptionEventType int
+
+const (
+	// SubscriptionDropped is reported when the underlying subscription channel
+	// was closed by the connection.
+	SubscriptionDropped SubscriptionEventType = iota
+	// SubscriptionRetrying is reported when an attempt to re-subscribe or to
+	// backfill missed items failed and will be retried.
+	SubscriptionRetrying
+	// SubscriptionRestored is reported once the subscription is re-established.
+	SubscriptionRestored
+	// SubscriptionBackfilled is reported after items missed while the
+	// subscription was down have been fetched.
+	SubscriptionBackfilled
+)
+
+func (t SubscriptionEventType) String() string {
+	switch t {
+	case SubscriptionDropped:
+		return "dropped"
+	case SubscriptionRetrying:
+		return "retrying"
+	case SubscriptionRestored:
+		return "restored"
+	case SubscriptionBackfilled:
+		return "backfilled"
+	default:
+		return fmt.Sprintf("SubscriptionEventType(%d)", int(t))
+	}
+}
+
+// SubscriptionEvent reports a change in the state of a resilient subscription.
+type SubscriptionEvent struct {
+	Type SubscriptionEventType
+	// Attempt is the number of consecutive failed attempts so far.
+	Attempt int
+	// Err is the error of the failed attempt, if any.
+	Err error
+	// From and To is the inclusive range of heights that was backfilled.
+	From, To uint64
+}
+
+// SubscriptionOption configures a resilient subscription.
+type SubscriptionOption func(*subscriptionConfig)
+
+type subscriptionConfig struct {
+	minBackoff time.Duration
+	maxBackoff time.Duration
+	onEvent    func(SubscriptionEvent)
+}
+
+func newSubscriptionConfig(opts []SubscriptionOption) *subscriptionConfig {
+	cfg := &subscriptionConfig{
+		minBackoff: 100 * time.Millisecond,
+		maxBackoff: 10 * time.Second,
+		onEvent:    func(SubscriptionEvent) {},
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+// WithSubscriptionBackoff sets the bounds of the exponential backoff between
+// attempts to re-subscribe.
+func WithSubscriptionBackoff(minDelay, maxDelay time.Duration) SubscriptionOption {
+	return func(c *subscriptionConfig) {
+		c.minBackoff = minDelay
+		c.maxBackoff = maxDelay
+	}
+}
+
+// WithSubscriptionEvents sets a callback that is notified about reconnects.
+// It is called synchronously and must not block.
+func WithSubscriptionEvents(onEvent func(SubscriptionEvent)) SubscriptionOption {
+	return func(c *subscriptionConfig) {
+		c.onEvent = onEvent
+	}
+}
+
+// backoff waits before the given attempt. It returns false if ctx is done.
+func (c *subscriptionConfig) backoff(ctx context.Context, attempt int) bool {
+	delay := c.minBackoff
+	for i := 1; i < attempt && delay < c.maxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > c.maxBackoff {
+		delay = c.maxBackoff
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// SubscribeHeaders subscribes to new ExtendedHeaders like Header.Subscribe, but
+// re-subscribes when the subscription drops. Headers missed in the meantime are
+// fetched with Header.GetRangeByHeight, so the returned channel delivers
+// headers in order and without gaps until ctx is done.
+func (c *Client) SubscribeHeaders(
+	ctx context.Context,
+	opts ...SubscriptionOption,
+) (<-chan *header.ExtendedHeader, error) {
+	cfg := newSubscriptionConfig(opts)
+	sub, err := c.Header.Subscribe(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan *header.ExtendedHeader)
+	go func() {
+		defer close(out)
+
+		send := func(h *header.ExtendedHeader) bool {
+			select {
+			case out <- h:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		var last *header.ExtendedHeader
+		for {
+			var (
+				h  *header.ExtendedHeader
+				ok bool
+			)
+			select {
+			case h, ok = <-sub:
+			case <-ctx.Done():
+				return
+			}
+
+			if !ok {
+				cfg.onEvent(SubscriptionEvent{Type: SubscriptionDropped})
+				if sub = c.resubscribeHeaders(ctx, cfg); sub == nil {
+					return
+				}
+				continue
+			}
+
+			if last != nil && h.Height() <= last.Height() {
+				// already delivered before the subscription was restored
+				continue
+			}
+			if last != nil && h.Height() > last.Height()+1 {
+				missed := c.backfillHeaders(ctx, cfg, last, h.Height())
+				if missed == nil && ctx.Err() != nil {
+					return
+				}
+				for _, m := range missed {
+					if !send(m) {
+						return
+					}
+				}
+			}
+			if !send(h) {
+				return
+			}
+			last = h
+		}
+	}()
+	return out, nil
+}
+
+func (c *Client) resubscribeHeaders(
+	ctx context.Context,
+	cfg *subscriptionConfig,
+) <-chan *header.ExtendedHeader {
+	for attempt := 1; cfg.backoff(ctx, attempt); attempt++ {
+		sub, err := c.Header.Subscribe(ctx)
+		if err != nil {
+			cfg.onEvent(SubscriptionEvent{Type: SubscriptionRetrying, Attempt: attempt, Err: err})
+			continue
+		}
+		cfg.onEvent(SubscriptionEvent{Type: SubscriptionRestored, Attempt: attempt})
+		return sub
+	}
+	return nil
+}
+
+// backfillHeaders fetches the headers in between from and to, both exclusive.
+// It retries until it succeeds or ctx is done, in which case it returns nil.
+func (c *Client) backfillHeaders(
+	ctx context.Context,
+	cfg *subscriptionConfig,
+	from *header.ExtendedHeader,
+	to uint64,
+) []*header.ExtendedHeader {
+	for attempt := 1; ; attempt++ {
+		missed, err := c.Header.GetRangeByHeight(ctx, from, to)
+		if err == nil {
+			cfg.onEvent(SubscriptionEvent{
+				Type:    SubscriptionBackfilled,
+				Attempt: attempt,
+				From:    from.Height() + 1,
+				To:      to - 1,
+			})
+			return missed
+		}
+		cfg.onEvent(SubscriptionEvent{Type: SubscriptionRetrying, Attempt: attempt, Err: err})
+		if !cfg.backoff(ctx, attempt) {
+			return nil
+		}
+	}
+}
+
+// SubscribeFraudProofs subscribes to fraud proofs of the given type like
+// Fraud.Subscribe, but re-subscribes when the subscription drops. Proofs
+// stored by the node in the meantime are fetched with Fraud.Get, so none are
+// missed, and none the node still stores is delivered twice.
+func (c *Client) SubscribeFraudProofs(
+	ctx context.Context,
+	proofType gofraud.ProofType,
+	opts ...SubscriptionOption,
+) (<-chan *fraud.Proof, error) {
+	cfg := newSubscriptionConfig(opts)
+	sub, err := c.Fraud.Subscribe(ctx, proofType)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan *fraud.Proof)
+	go func() {
+		defer close(out)
+
+		// seen holds the heights of the delivered proofs by key
+		seen := make(map[string]uint64)
+		send := func(p *fraud.Proof) bool {
+			if key, ok := proofKey(p); ok {
+				if _, dup := seen[key]; dup {
+					return true
+				}
+				seen[key] = p.Height()
+			}
+			select {
+			case out <- p:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		for {
+			select {
+			case p, ok := <-sub:
+				if ok {
+					if !send(p) {
+						return
+					}
+					continue
+				}
+			case <-ctx.Done():
+				return
+			}
+
+			cfg.onEvent(SubscriptionEvent{Type: SubscriptionDropped})
+			if sub = c.resubscribeFraudProofs(ctx, cfg, proofType); sub == nil {
+				return
+			}
+
+			stored, err := c.Fraud.Get(ctx, proofType)
+			if err != nil {
+				// proofs are gossiped repeatedly, so a failed backfill is not
+				// worth holding the subscription for
+				cfg.onEvent(SubscriptionEvent{Type: SubscriptionRetrying, Err: err})
+				continue
+			}
+			for i := range stored {
+				if !send(&stored[i]) {
+					return
+				}
+			}
+			pruneProofKeys(seen, stored)
+			cfg.onEvent(SubscriptionEvent{Type: SubscriptionBackfilled})
+		}
+	}()
+	return out, nil
+}
+
+func (c *Client) resubscribeFraudProofs(
+	ctx context.Context,
+	cfg *subscriptionConfig,
+	proofType gofraud.ProofType,
+) <-chan *fraud.Proof {
+	for attempt := 1; cfg.backoff(ctx, attempt); attempt++ {
+		sub, err := c.Fraud.Subscribe(ctx, proofType)
+		if err != nil {
+			cfg.onEvent(SubscriptionEvent{Type: SubscriptionRetrying, Attempt: attempt, Err: err})
+			continue
+		}
+		cfg.onEvent(SubscriptionEvent{Type: SubscriptionRestored, Attempt: attempt})
+		return sub
+	}
+	return nil
+}
+
+// pruneProofKeys forgets the proofs below the lowest height the node still
+// stores proofs for, as they are not fetched again.
+func pruneProofKeys(seen map[string]uint64, stored []fraud.Proof) {
+	low := uint64(math.MaxUint64)
+	for i := range stored {
+		if stored[i].Proof != nil && stored[i].Height() < low {
+			low = stored[i].Height()
+		}
+	}
+	for key, height := range seen {
+		if height < low {
+			delete(seen, key)
+		}
+	}
+}
+
+// proofKey identifies a fraud proof by the header it was created for.
+func proofKey(p *fraud.Proof) (string, bool) {
+	if p == nil || p.Proof == nil {
+		return "", false
+	}
+	return fmt.Sprintf("%d/%X", p.Height(), p.HeaderHash()), true
+}
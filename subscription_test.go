@@ -0,0 +1,221 @@
+package client
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	gofraud "github.com/celestiaorg/go-fraud"
+	"github.com/stretchr/testify/require"
+
+	"github.com/celestiaorg/celestia-openrpc/testnode"
+	"github.com/celestiaorg/celestia-openrpc/types/core"
+	"github.com/celestiaorg/celestia-openrpc/types/fraud"
+	"github.com/celestiaorg/celestia-openrpc/types/header"
+)
+
+func testHeader(height uint64) *header.ExtendedHeader {
+	return &header.ExtendedHeader{Commit: &core.Commit{Height: int64(height)}}
+}
+
+// TestSubscribeHeaders_Backfill ensures that headers missed while the
+// subscription was down are backfilled and duplicates are dropped.
+func TestSubscribeHeaders_Backfill(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	subs := []chan *header.ExtendedHeader{
+		make(chan *header.ExtendedHeader, 2),
+		make(chan *header.ExtendedHeader, 2),
+	}
+	subs[0] <- testHeader(1)
+	subs[0] <- testHeader(2)
+	close(subs[0])
+	subs[1] <- testHeader(2)
+	subs[1] <- testHeader(5)
+
+	var (
+		calls  int
+		events []SubscriptionEventType
+	)
+	c := &Client{Header: header.API{
+		Subscribe: func(context.Context) (<-chan *header.ExtendedHeader, error) {
+			defer func() { calls++ }()
+			switch calls {
+			case 0:
+				return subs[0], nil
+			case 1:
+				return nil, errors.New("connection refused")
+			default:
+				return subs[1], nil
+			}
+		},
+		GetRangeByHeight: func(
+			_ context.Context,
+			from *header.ExtendedHeader,
+			to uint64,
+		) ([]*header.ExtendedHeader, error) {
+			var out []*header.ExtendedHeader
+			for h := from.Height() + 1; h < to; h++ {
+				out = append(out, testHeader(h))
+			}
+			return out, nil
+		},
+	}}
+
+	sub, err := c.SubscribeHeaders(ctx,
+		WithSubscriptionBackoff(time.Millisecond, time.Millisecond),
+		WithSubscriptionEvents(func(ev SubscriptionEvent) {
+			events = append(events, ev.Type)
+		}),
+	)
+	require.NoError(t, err)
+
+	for height := uint64(1); height <= 5; height++ {
+		select {
+		case h := <-sub:
+			require.Equal(t, height, h.Height())
+		case <-ctx.Done():
+			t.Fatal(ctx.Err())
+		}
+	}
+	require.Equal(t, []SubscriptionEventType{
+		SubscriptionDropped,
+		SubscriptionRetrying,
+		SubscriptionRestored,
+		SubscriptionBackfilled,
+	}, events)
+}
+
+const testProofType gofraud.ProofType = "test"
+
+// testProof is a fraud proof for the header at height.
+type testProof struct {
+	height uint64
+}
+
+func newTestProof(height uint64) *fraud.Proof {
+	return &fraud.Proof{Proof: &testProof{height: height}}
+}
+
+func (p *testProof) Type() gofraud.ProofType {
+	return testProofType
+}
+
+func (p *testProof) HeaderHash() []byte {
+	return binary.BigEndian.AppendUint64(nil, p.height)
+}
+
+func (p *testProof) Height() uint64 {
+	return p.height
+}
+
+func (p *testProof) Validate(*header.ExtendedHeader) error {
+	return nil
+}
+
+func (p *testProof) MarshalBinary() ([]byte, error) {
+	return binary.BigEndian.AppendUint64(nil, p.height), nil
+}
+
+func (p *testProof) UnmarshalBinary(data []byte) error {
+	if len(data) != 8 {
+		return errors.New("invalid test proof")
+	}
+	p.height = binary.BigEndian.Uint64(data)
+	return nil
+}
+
+func init() {
+	fraud.Unmarshaler.Unmarshalers[testProofType] = func(data []byte) (gofraud.Proof[*header.ExtendedHeader], error) {
+		p := &testProof{}
+		return p, p.UnmarshalBinary(data)
+	}
+}
+
+// TestSubscribeFraudProofs ensures that proofs are delivered once, even when
+// gossiped again or fetched after a reconnect, and that proofs without a key
+// are delivered as they are.
+func TestSubscribeFraudProofs(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	node, err := testnode.New()
+	require.NoError(t, err)
+	defer node.Close()
+	c, err := NewClient(ctx, node.URL(), "", WithReconnect(time.Millisecond, 10*time.Millisecond))
+	require.NoError(t, err)
+	defer c.Close()
+
+	var (
+		mu     sync.Mutex
+		events []SubscriptionEventType
+	)
+	sub, err := c.SubscribeFraudProofs(ctx, testProofType,
+		WithSubscriptionBackoff(time.Millisecond, 10*time.Millisecond),
+		WithSubscriptionEvents(func(ev SubscriptionEvent) {
+			mu.Lock()
+			defer mu.Unlock()
+			events = append(events, ev.Type)
+		}),
+	)
+	require.NoError(t, err)
+
+	next := func() *fraud.Proof {
+		select {
+		case p, ok := <-sub:
+			require.True(t, ok)
+			return p
+		case <-ctx.Done():
+			t.Fatal(ctx.Err())
+			return nil
+		}
+	}
+
+	node.PublishFraudProof(testProofType, newTestProof(1))
+	require.EqualValues(t, 1, next().Height())
+	// a proof without a key cannot be told apart from others
+	node.PublishFraudProof(testProofType, nil)
+	require.Nil(t, next())
+	// gossiped again
+	node.PublishFraudProof(testProofType, newTestProof(1))
+	node.PublishFraudProof(testProofType, newTestProof(2))
+	require.EqualValues(t, 2, next().Height())
+
+	// published while the subscription is down, so it may only be fetched
+	node.DropConnections()
+	node.PublishFraudProof(testProofType, newTestProof(3))
+	require.EqualValues(t, 3, next().Height())
+	node.PublishFraudProof(testProofType, newTestProof(4))
+	require.EqualValues(t, 4, next().Height())
+
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(events) > 0 && events[len(events)-1] == SubscriptionBackfilled
+	}, time.Second, time.Millisecond)
+	mu.Lock()
+	defer mu.Unlock()
+	require.Equal(t, SubscriptionDropped, events[0])
+	require.Contains(t, events, SubscriptionRestored)
+}
+
+func TestPruneProofKeys(t *testing.T) {
+	seen := make(map[string]uint64)
+	for height := uint64(1); height <= 3; height++ {
+		key, ok := proofKey(newTestProof(height))
+		require.True(t, ok)
+		seen[key] = height
+	}
+	key, _ := proofKey(newTestProof(3))
+
+	pruneProofKeys(seen, []fraud.Proof{{}, *newTestProof(3), *newTestProof(2)})
+	require.Len(t, seen, 2)
+	pruneProofKeys(seen, []fraud.Proof{*newTestProof(3)})
+	require.Equal(t, map[string]uint64{key: 3}, seen)
+	pruneProofKeys(seen, nil)
+	require.Empty(t, seen)
+}
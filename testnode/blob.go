@@ -0,0 +1,180 @@
+package testnode
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"math"
+
+	sdkmath "cosmossdk.io/math"
+
+	"github.com/celestiaorg/celestia-openrpc/internal/blobindex"
+	"github.com/celestiaorg/celestia-openrpc/types/appconsts"
+	"github.com/celestiaorg/celestia-openrpc/types/blob"
+	"github.com/celestiaorg/celestia-openrpc/types/share"
+)
+
+// indexed returns a copy of a blob with its index in the extended data square
+// set, as celestia-node returns it.
+func (b *block) indexed(sb *storedBlob) (*blob.Blob, error) {
+	bl, err := blob.NewBlob(uint8(sb.ShareVersion), sb.Namespace().Bytes(), sb.Data)
+	if err != nil {
+		return nil, err
+	}
+	width := int(b.eds.Width() / 2)
+	blobindex.Set(bl, sb.index/width*2*width+sb.index%width)
+	return bl, nil
+}
+
+// proof proves the shares of the namespace of a blob in each row the blob
+// spans, as celestia-node does.
+func (b *block) proof(sb *storedBlob) (blob.Proof, error) {
+	width := int(b.eds.Width() / 2)
+	var proof blob.Proof
+	for row := sb.index / width; row <= (sb.index+sb.shares-1)/width; row++ {
+		p, err := namespaceProof(b.eds, uint(row), sb.Namespace().Bytes())
+		if err != nil {
+			return nil, err
+		}
+		proof = append(proof, p)
+	}
+	return proof, nil
+}
+
+// verify reports whether proof proves the shares of a blob.
+func (b *block) verify(sb *storedBlob, proof blob.Proof) bool {
+	width := int(b.eds.Width() / 2)
+	row := sb.index / width
+	if len(proof) != (sb.index+sb.shares-1)/width-row+1 {
+		return false
+	}
+	for _, p := range proof {
+		if p == nil || p.Start() < 0 || p.End() > width || p.Start() >= p.End() {
+			return false
+		}
+		leaves := b.eds.Row(uint(row))[p.Start():p.End()]
+		if !p.VerifyInclusion(sha256.New(), sb.Namespace().Bytes(), leaves, b.header.DAH.RowRoots[row]) {
+			return false
+		}
+		row++
+	}
+	return true
+}
+
+// pfbFee returns the fee paid for blobs at gasPrice, the default gas price
+// if negative.
+func pfbFee(blobs []*blob.Blob, gasPrice float64) sdkmath.Int {
+	if gasPrice < 0 {
+		gasPrice = appconsts.DefaultMinGasPrice
+	}
+	gas := pfbGasFixedCost
+	for _, b := range blobs {
+		gas += appconsts.DefaultGasPerBlobByte * len(b.Data)
+	}
+	return sdkmath.NewInt(int64(math.Ceil(float64(gas) * gasPrice)))
+}
+
+// pfbGasFixedCost is the gas consumed by a PFB besides its blobs.
+const pfbGasFixedCost = 75_000
+
+// pfbTx returns the tx paying for blobs, which is made up for the test node.
+func pfbTx(blobs []*blob.Blob) []byte {
+	tx := []byte("pfb")
+	for _, b := range blobs {
+		tx = append(tx, b.Commitment...)
+	}
+	return tx
+}
+
+type blobModule struct {
+	n *Node
+}
+
+func (m *blobModule) Submit(ctx context.Context, blobs []*blob.Blob, gasPrice float64) (uint64, error) {
+	if len(blobs) == 0 {
+		return 0, errors.New("testnode: no blobs to submit")
+	}
+	if err := m.n.charge(m.n.account, pfbFee(blobs, gasPrice)); err != nil {
+		return 0, err
+	}
+	return m.n.submit(ctx, pfbTx(blobs), blobs)
+}
+
+func (m *blobModule) Get(
+	_ context.Context,
+	height uint64,
+	ns share.Namespace,
+	com blob.Commitment,
+) (*blob.Blob, error) {
+	b, err := m.n.blockAt(height)
+	if err != nil {
+		return nil, err
+	}
+	sb, ok := b.byKey[blobKey(ns, com)]
+	if !ok {
+		return nil, blob.ErrBlobNotFound
+	}
+	return b.indexed(sb)
+}
+
+func (m *blobModule) GetAll(_ context.Context, height uint64, nss []share.Namespace) ([]*blob.Blob, error) {
+	b, err := m.n.blockAt(height)
+	if err != nil {
+		return nil, err
+	}
+	var blobs []*blob.Blob
+	for _, ns := range nss {
+		for _, sb := range b.blobs {
+			if ns.Equals(sb.Namespace().Bytes()) {
+				bl, err := b.indexed(sb)
+				if err != nil {
+					return nil, err
+				}
+				blobs = append(blobs, bl)
+			}
+		}
+	}
+	if len(blobs) == 0 {
+		return nil, blob.ErrBlobNotFound
+	}
+	return blobs, nil
+}
+
+func (m *blobModule) GetProof(
+	_ context.Context,
+	height uint64,
+	ns share.Namespace,
+	com blob.Commitment,
+) (*blob.Proof, error) {
+	b, err := m.n.blockAt(height)
+	if err != nil {
+		return nil, err
+	}
+	sb, ok := b.byKey[blobKey(ns, com)]
+	if !ok {
+		return nil, blob.ErrBlobNotFound
+	}
+	proof, err := b.proof(sb)
+	if err != nil {
+		return nil, err
+	}
+	return &proof, nil
+}
+
+func (m *blobModule) Included(
+	_ context.Context,
+	height uint64,
+	ns share.Namespace,
+	proof *blob.Proof,
+	com blob.Commitment,
+) (bool, error) {
+	b, err := m.n.blockAt(height)
+	if err != nil {
+		return false, err
+	}
+	sb, ok := b.byKey[blobKey(ns, com)]
+	if !ok || proof == nil {
+		return false, nil
+	}
+	return b.verify(sb, *proof), nil
+}
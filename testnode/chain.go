@@ -0,0 +1,242 @@
+package testnode
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"sort"
+	"time"
+
+	cmversion "github.com/cometbft/cometbft/proto/tendermint/version"
+
+	"github.com/celestiaorg/nmt"
+	"github.com/celestiaorg/rsmt2d"
+
+	"github.com/celestiaorg/celestia-openrpc/types/appconsts"
+	"github.com/celestiaorg/celestia-openrpc/types/blob"
+	"github.com/celestiaorg/celestia-openrpc/types/core"
+	"github.com/celestiaorg/celestia-openrpc/types/header"
+	"github.com/celestiaorg/celestia-openrpc/types/share"
+	"github.com/celestiaorg/celestia-openrpc/types/square"
+)
+
+var errTooLarge = errors.New("testnode: tx does not fit in a block")
+
+// block is a produced block.
+type block struct {
+	header *header.ExtendedHeader
+	eds    *rsmt2d.ExtendedDataSquare
+	// blobs are ordered by their index, and keyed by namespace and commitment.
+	blobs []*storedBlob
+	byKey map[string]*storedBlob
+}
+
+// storedBlob is a blob laid out in the original data square.
+type storedBlob struct {
+	*blob.Blob
+	// index is the index of the first share in the original data square.
+	index  int
+	shares int
+}
+
+func blobKey(ns share.Namespace, com blob.Commitment) string {
+	return string(ns) + string(com)
+}
+
+// pendingTx is a tx waiting to be included in a block.
+type pendingTx struct {
+	tx []byte
+	// blobs are paid for by the tx, which is a PFB if there are any.
+	blobs []*blob.Blob
+	// included receives the height of the block including the tx.
+	included chan uint64
+}
+
+func (p *pendingTx) appendTo(b *square.Builder) bool {
+	if len(p.blobs) == 0 {
+		return b.AppendTx(p.tx)
+	}
+	return b.AppendBlobTx(p.tx, p.blobs...)
+}
+
+func newSquareBuilder() (*square.Builder, error) {
+	return square.NewBuilder(appconsts.DefaultSquareSizeUpperBound, appconsts.DefaultSubtreeRootThreshold)
+}
+
+// submit queues a tx for the next block and waits for its inclusion,
+// returning the height of the block.
+func (n *Node) submit(ctx context.Context, tx []byte, blobs []*blob.Blob) (uint64, error) {
+	p := &pendingTx{tx: tx, blobs: blobs, included: make(chan uint64, 1)}
+	builder, err := newSquareBuilder()
+	if err != nil {
+		return 0, err
+	}
+	if !p.appendTo(builder) {
+		return 0, errTooLarge
+	}
+
+	n.mu.Lock()
+	n.pending = append(n.pending, p)
+	n.mu.Unlock()
+	if n.cfg.blockTime == 0 {
+		if _, err := n.ProduceBlock(); err != nil {
+			return 0, err
+		}
+	}
+
+	select {
+	case height := <-p.included:
+		return height, nil
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+}
+
+// ProduceBlock produces a block with the pending txs that fit in it.
+func (n *Node) ProduceBlock() (*header.ExtendedHeader, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	builder, err := newSquareBuilder()
+	if err != nil {
+		return nil, err
+	}
+	var included, deferred []*pendingTx
+	for _, p := range n.pending {
+		if p.appendTo(builder) {
+			included = append(included, p)
+		} else {
+			deferred = append(deferred, p)
+		}
+	}
+	sq, err := builder.Export()
+	if err != nil {
+		return nil, err
+	}
+
+	b := &block{byKey: map[string]*storedBlob{}, eds: sq.EDS}
+	pfbIndex := 0
+	for _, p := range included {
+		if len(p.blobs) == 0 {
+			continue
+		}
+		for i, bl := range p.blobs {
+			r, err := sq.BlobRange(pfbIndex, i)
+			if err != nil {
+				return nil, err
+			}
+			stored := &storedBlob{Blob: bl, index: r.Start, shares: r.End - r.Start}
+			b.blobs = append(b.blobs, stored)
+			b.byKey[blobKey(bl.Namespace().Bytes(), bl.Commitment)] = stored
+		}
+		pfbIndex++
+	}
+	sort.Slice(b.blobs, func(i, j int) bool {
+		return b.blobs[i].index < b.blobs[j].index
+	})
+	b.header = n.newHeader(sq.DAH)
+
+	n.blocks = append(n.blocks, b)
+	n.pending = deferred
+	for _, p := range included {
+		p.included <- b.header.Height()
+	}
+	close(n.newBlock)
+	n.newBlock = make(chan struct{})
+	return b.header, nil
+}
+
+func (n *Node) newHeader(dah *header.DataAvailabilityHeader) *header.ExtendedHeader {
+	raw := header.RawHeader{
+		Version:  cmversion.Consensus{Block: 11, App: appconsts.LatestVersion},
+		ChainID:  n.cfg.chainID,
+		Height:   int64(len(n.blocks) + 1),
+		Time:     time.Now().UTC(),
+		DataHash: dah.Hash(),
+	}
+	if len(n.blocks) > 0 {
+		raw.LastBlockID = n.blocks[len(n.blocks)-1].header.Commit.BlockID
+	}
+
+	hash := sha256.New()
+	hash.Write([]byte(raw.ChainID))
+	hash.Write(binary.BigEndian.AppendUint64(nil, uint64(raw.Height)))
+	hash.Write(binary.BigEndian.AppendUint64(nil, uint64(raw.Time.UnixNano())))
+	hash.Write(raw.LastBlockID.Hash)
+	hash.Write(raw.DataHash)
+
+	return &header.ExtendedHeader{
+		RawHeader: raw,
+		Commit: &core.Commit{
+			Height:  raw.Height,
+			BlockID: core.BlockID{Hash: hash.Sum(nil)},
+		},
+		ValidatorSet: &core.ValidatorSet{},
+		DAH:          dah,
+	}
+}
+
+// blockAt returns the block at height, failing for heights not produced yet.
+func (n *Node) blockAt(height uint64) (*block, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if height == 0 {
+		return nil, errors.New("header: height must be bigger than zero")
+	}
+	if head := uint64(len(n.blocks)); height > head {
+		return nil, &header.HeightFromFutureError{NetworkHeight: head, RequestedHeight: height}
+	}
+	return n.blocks[height-1], nil
+}
+
+// blockOf returns the block of a header.
+func (n *Node) blockOf(h *header.ExtendedHeader) (*block, error) {
+	if h == nil || h.Commit == nil {
+		return nil, errors.New("testnode: missing header")
+	}
+	b, err := n.blockAt(h.Height())
+	if err != nil {
+		return nil, err
+	}
+	if !b.header.DAH.Equals(h.DAH) {
+		return nil, errors.New("share: data not available")
+	}
+	return b, nil
+}
+
+// waitForHeight waits for the block at height to be produced.
+func (n *Node) waitForHeight(ctx context.Context, height uint64) (*block, error) {
+	for {
+		n.mu.Lock()
+		if height <= uint64(len(n.blocks)) {
+			b := n.blocks[height-1]
+			n.mu.Unlock()
+			return b, nil
+		}
+		newBlock := n.newBlock
+		n.mu.Unlock()
+
+		select {
+		case <-newBlock:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// namespaceProof proves the shares of namespace ns in a row of the extended
+// data square, or their absence.
+func namespaceProof(eds *rsmt2d.ExtendedDataSquare, row uint, ns share.Namespace) (*nmt.Proof, error) {
+	t := square.NewErasuredNamespacedMerkleTree(eds.Width()/2, row)
+	for _, sh := range eds.Row(row) {
+		if err := t.Push(sh); err != nil {
+			return nil, err
+		}
+	}
+	proof, err := t.ProveNamespace(ns.ToNMT())
+	if err != nil {
+		return nil, err
+	}
+	return &proof, nil
+}
@@ -0,0 +1,165 @@
+package testnode
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/celestiaorg/celestia-openrpc/types/appconsts"
+	"github.com/celestiaorg/celestia-openrpc/types/blob"
+	"github.com/celestiaorg/celestia-openrpc/types/da"
+	"github.com/celestiaorg/celestia-openrpc/types/share"
+)
+
+// heightLen is the length of the height prefixing the commitment in an ID.
+const heightLen = 8
+
+func makeID(height uint64, com blob.Commitment) da.ID {
+	id := make([]byte, heightLen, heightLen+len(com))
+	binary.LittleEndian.PutUint64(id, height)
+	return append(id, com...)
+}
+
+func splitID(id da.ID) (uint64, blob.Commitment, error) {
+	if len(id) <= heightLen {
+		return 0, nil, fmt.Errorf("da: invalid ID of %d bytes", len(id))
+	}
+	return binary.LittleEndian.Uint64(id[:heightLen]), id[heightLen:], nil
+}
+
+type daModule struct {
+	n *Node
+}
+
+func (m *daModule) MaxBlobSize(context.Context) (uint64, error) {
+	return appconsts.DefaultMaxBytes, nil
+}
+
+func (m *daModule) Get(_ context.Context, ids []da.ID, ns da.Namespace) ([]da.Blob, error) {
+	blobs := make([]da.Blob, 0, len(ids))
+	for _, id := range ids {
+		sb, _, err := m.lookup(id, ns)
+		if err != nil {
+			return nil, err
+		}
+		blobs = append(blobs, sb.Data)
+	}
+	return blobs, nil
+}
+
+func (m *daModule) GetIDs(_ context.Context, height uint64, ns da.Namespace) ([]da.ID, error) {
+	b, err := m.n.blockAt(height)
+	if err != nil {
+		return nil, err
+	}
+	var ids []da.ID
+	for _, sb := range b.blobs {
+		if share.Namespace(ns).Equals(sb.Namespace().Bytes()) {
+			ids = append(ids, makeID(height, sb.Commitment))
+		}
+	}
+	return ids, nil
+}
+
+func (m *daModule) GetProofs(_ context.Context, ids []da.ID, ns da.Namespace) ([]da.Proof, error) {
+	proofs := make([]da.Proof, 0, len(ids))
+	for _, id := range ids {
+		sb, b, err := m.lookup(id, ns)
+		if err != nil {
+			return nil, err
+		}
+		proof, err := b.proof(sb)
+		if err != nil {
+			return nil, err
+		}
+		encoded, err := json.Marshal(proof)
+		if err != nil {
+			return nil, err
+		}
+		proofs = append(proofs, encoded)
+	}
+	return proofs, nil
+}
+
+func (m *daModule) Commit(_ context.Context, blobs []da.Blob, ns da.Namespace) ([]da.Commitment, error) {
+	commitments := make([]da.Commitment, 0, len(blobs))
+	for _, data := range blobs {
+		b, err := blob.NewBlobV0(ns, data)
+		if err != nil {
+			return nil, err
+		}
+		commitments = append(commitments, b.Commitment)
+	}
+	return commitments, nil
+}
+
+func (m *daModule) Validate(
+	_ context.Context,
+	ids []da.ID,
+	proofs []da.Proof,
+	ns da.Namespace,
+) ([]bool, error) {
+	if len(ids) != len(proofs) {
+		return nil, errors.New("da: number of IDs and proofs differ")
+	}
+	included := make([]bool, len(ids))
+	for i, id := range ids {
+		sb, b, err := m.lookup(id, ns)
+		if errors.Is(err, blob.ErrBlobNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		var proof blob.Proof
+		if err := json.Unmarshal(proofs[i], &proof); err != nil {
+			return nil, err
+		}
+		included[i] = b.verify(sb, proof)
+	}
+	return included, nil
+}
+
+func (m *daModule) Submit(
+	ctx context.Context,
+	blobs []da.Blob,
+	gasPrice float64,
+	ns da.Namespace,
+) ([]da.ID, error) {
+	submitted := make([]*blob.Blob, 0, len(blobs))
+	for _, data := range blobs {
+		b, err := blob.NewBlobV0(ns, data)
+		if err != nil {
+			return nil, err
+		}
+		submitted = append(submitted, b)
+	}
+	height, err := (&blobModule{m.n}).Submit(ctx, submitted, gasPrice)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]da.ID, len(submitted))
+	for i, b := range submitted {
+		ids[i] = makeID(height, b.Commitment)
+	}
+	return ids, nil
+}
+
+// lookup returns the blob identified by id and its block.
+func (m *daModule) lookup(id da.ID, ns da.Namespace) (*storedBlob, *block, error) {
+	height, com, err := splitID(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	b, err := m.n.blockAt(height)
+	if err != nil {
+		return nil, nil, err
+	}
+	sb, ok := b.byKey[blobKey(ns, com)]
+	if !ok {
+		return nil, nil, blob.ErrBlobNotFound
+	}
+	return sb, b, nil
+}
@@ -0,0 +1,81 @@
+package testnode
+
+import (
+	"context"
+
+	gofraud "github.com/celestiaorg/go-fraud"
+
+	"github.com/celestiaorg/celestia-openrpc/types/fraud"
+)
+
+// PublishFraudProof stores a fraud proof of the given type and sends it to
+// the subscribers of the type. A nil proof is only sent, as null.
+func (n *Node) PublishFraudProof(proofType gofraud.ProofType, proof *fraud.Proof) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.fraudProofs[proofType] = append(n.fraudProofs[proofType], proof)
+	close(n.newFraudProof)
+	n.newFraudProof = make(chan struct{})
+}
+
+// fraudProofsFrom waits for the proofs of the given type published from the
+// index next on.
+func (n *Node) fraudProofsFrom(ctx context.Context, proofType gofraud.ProofType, next int) ([]*fraud.Proof, error) {
+	for {
+		n.mu.Lock()
+		if proofs := n.fraudProofs[proofType]; next < len(proofs) {
+			n.mu.Unlock()
+			return proofs[next:], nil
+		}
+		newFraudProof := n.newFraudProof
+		n.mu.Unlock()
+
+		select {
+		case <-newFraudProof:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// fraudModule serves the fraud proofs published with Node.PublishFraudProof.
+type fraudModule struct {
+	n *Node
+}
+
+func (m *fraudModule) Subscribe(ctx context.Context, proofType gofraud.ProofType) (<-chan *fraud.Proof, error) {
+	proofs := make(chan *fraud.Proof)
+	m.n.mu.Lock()
+	next := len(m.n.fraudProofs[proofType])
+	m.n.mu.Unlock()
+	go func() {
+		defer close(proofs)
+		for {
+			published, err := m.n.fraudProofsFrom(ctx, proofType, next)
+			if err != nil {
+				return
+			}
+			for _, p := range published {
+				select {
+				case proofs <- p:
+				case <-ctx.Done():
+					return
+				}
+			}
+			next += len(published)
+		}
+	}()
+	return proofs, nil
+}
+
+func (m *fraudModule) Get(_ context.Context, proofType gofraud.ProofType) ([]fraud.Proof, error) {
+	m.n.mu.Lock()
+	defer m.n.mu.Unlock()
+	stored := []fraud.Proof{}
+	for _, p := range m.n.fraudProofs[proofType] {
+		if p != nil {
+			stored = append(stored, *p)
+		}
+	}
+	return stored, nil
+}
@@ -0,0 +1,116 @@
+package testnode
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	libhead "github.com/celestiaorg/go-header"
+	"github.com/celestiaorg/go-header/sync"
+
+	"github.com/celestiaorg/celestia-openrpc/types/header"
+)
+
+type headerModule struct {
+	n *Node
+}
+
+func (m *headerModule) LocalHead(context.Context) (*header.ExtendedHeader, error) {
+	return m.n.Head(), nil
+}
+
+func (m *headerModule) GetByHash(_ context.Context, hash libhead.Hash) (*header.ExtendedHeader, error) {
+	m.n.mu.Lock()
+	defer m.n.mu.Unlock()
+	for _, b := range m.n.blocks {
+		if bytes.Equal(b.header.Hash(), hash) {
+			return b.header, nil
+		}
+	}
+	return nil, libhead.ErrNotFound
+}
+
+func (m *headerModule) GetRangeByHeight(
+	_ context.Context,
+	from *header.ExtendedHeader,
+	to uint64,
+) ([]*header.ExtendedHeader, error) {
+	if from == nil || from.Commit == nil {
+		return nil, fmt.Errorf("header: missing header to range from")
+	}
+	if to <= from.Height()+1 {
+		return nil, fmt.Errorf("header: invalid range: from %d to %d", from.Height(), to)
+	}
+	if _, err := m.n.blockAt(to - 1); err != nil {
+		return nil, err
+	}
+
+	m.n.mu.Lock()
+	defer m.n.mu.Unlock()
+	headers := make([]*header.ExtendedHeader, 0, to-from.Height()-1)
+	for height := from.Height() + 1; height < to; height++ {
+		headers = append(headers, m.n.blocks[height-1].header)
+	}
+	return headers, nil
+}
+
+func (m *headerModule) GetByHeight(_ context.Context, height uint64) (*header.ExtendedHeader, error) {
+	b, err := m.n.blockAt(height)
+	if err != nil {
+		return nil, err
+	}
+	return b.header, nil
+}
+
+func (m *headerModule) WaitForHeight(ctx context.Context, height uint64) (*header.ExtendedHeader, error) {
+	if height == 0 {
+		return nil, fmt.Errorf("header: height must be bigger than zero")
+	}
+	b, err := m.n.waitForHeight(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+	return b.header, nil
+}
+
+func (m *headerModule) SyncState(context.Context) (sync.State, error) {
+	head := m.n.Head()
+	return sync.State{
+		Height:     head.Height(),
+		FromHeight: 1,
+		ToHeight:   head.Height(),
+		ToHash:     head.Hash(),
+		Start:      m.n.started,
+		End:        head.Time(),
+	}, nil
+}
+
+func (m *headerModule) SyncWait(context.Context) error {
+	return nil
+}
+
+func (m *headerModule) NetworkHead(ctx context.Context) (*header.ExtendedHeader, error) {
+	return m.LocalHead(ctx)
+}
+
+// Subscribe sends the headers of the blocks produced from now on, until ctx
+// is done.
+func (m *headerModule) Subscribe(ctx context.Context) (<-chan *header.ExtendedHeader, error) {
+	headers := make(chan *header.ExtendedHeader)
+	next := m.n.Head().Height() + 1
+	go func() {
+		defer close(headers)
+		for ; ; next++ {
+			b, err := m.n.waitForHeight(ctx, next)
+			if err != nil {
+				return
+			}
+			select {
+			case headers <- b.header:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return headers, nil
+}
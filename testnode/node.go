@@ -0,0 +1,38 @@
+package testnode
+
+import (
+	"context"
+
+	"github.com/filecoin-project/go-jsonrpc/auth"
+
+	"github.com/celestiaorg/celestia-openrpc/types/node"
+)
+
+// bridge is the node.Type of bridge nodes, which store every block, as the
+// test node does.
+const bridge node.Type = 1
+
+// allPermissions are granted to any token.
+var allPermissions = []auth.Permission{"public", "read", "write", "admin"}
+
+type nodeModule struct{}
+
+func (nodeModule) Info(context.Context) (node.Info, error) {
+	return node.Info{Type: bridge, APIVersion: APIVersion}, nil
+}
+
+func (nodeModule) Ready(context.Context) (bool, error) {
+	return true, nil
+}
+
+func (nodeModule) LogLevelSet(context.Context, string, string) error {
+	return nil
+}
+
+func (nodeModule) AuthVerify(context.Context, string) ([]auth.Permission, error) {
+	return allPermissions, nil
+}
+
+func (nodeModule) AuthNew(context.Context, []auth.Permission) ([]byte, error) {
+	return []byte(APIVersion), nil
+}
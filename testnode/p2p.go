@@ -0,0 +1,135 @@
+package testnode
+
+import (
+	"context"
+	"crypto/rand"
+	"sync"
+
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/libp2p/go-libp2p/core/metrics"
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/protocol"
+	rcmgr "github.com/libp2p/go-libp2p/p2p/host/resource-manager"
+)
+
+// p2pModule is a host without peers, which keeps track of the peers blocked
+// and protected through the API.
+type p2pModule struct {
+	id peer.ID
+
+	mu        sync.Mutex
+	blocked   map[peer.ID]bool
+	protected map[peer.ID]map[string]bool
+}
+
+func newP2PModule() (*p2pModule, error) {
+	_, pub, err := crypto.GenerateEd25519Key(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	id, err := peer.IDFromPublicKey(pub)
+	if err != nil {
+		return nil, err
+	}
+	return &p2pModule{
+		id:        id,
+		blocked:   map[peer.ID]bool{},
+		protected: map[peer.ID]map[string]bool{},
+	}, nil
+}
+
+func (m *p2pModule) Info(context.Context) (peer.AddrInfo, error) {
+	return peer.AddrInfo{ID: m.id}, nil
+}
+
+func (m *p2pModule) Peers(context.Context) ([]peer.ID, error) {
+	return []peer.ID{}, nil
+}
+
+func (m *p2pModule) PeerInfo(_ context.Context, id peer.ID) (peer.AddrInfo, error) {
+	return peer.AddrInfo{ID: id}, nil
+}
+
+func (m *p2pModule) Connect(context.Context, peer.AddrInfo) error {
+	return nil
+}
+
+func (m *p2pModule) ClosePeer(context.Context, peer.ID) error {
+	return nil
+}
+
+func (m *p2pModule) Connectedness(context.Context, peer.ID) (network.Connectedness, error) {
+	return network.NotConnected, nil
+}
+
+func (m *p2pModule) NATStatus(context.Context) (network.Reachability, error) {
+	return network.ReachabilityUnknown, nil
+}
+
+func (m *p2pModule) BlockPeer(_ context.Context, id peer.ID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.blocked[id] = true
+	return nil
+}
+
+func (m *p2pModule) UnblockPeer(_ context.Context, id peer.ID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.blocked, id)
+	return nil
+}
+
+func (m *p2pModule) ListBlockedPeers(context.Context) ([]peer.ID, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	ids := make([]peer.ID, 0, len(m.blocked))
+	for id := range m.blocked {
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func (m *p2pModule) Protect(_ context.Context, id peer.ID, tag string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.protected[id] == nil {
+		m.protected[id] = map[string]bool{}
+	}
+	m.protected[id][tag] = true
+	return nil
+}
+
+func (m *p2pModule) Unprotect(_ context.Context, id peer.ID, tag string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.protected[id], tag)
+	return len(m.protected[id]) > 0, nil
+}
+
+func (m *p2pModule) IsProtected(_ context.Context, id peer.ID, tag string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.protected[id][tag], nil
+}
+
+func (m *p2pModule) BandwidthStats(context.Context) (metrics.Stats, error) {
+	return metrics.Stats{}, nil
+}
+
+func (m *p2pModule) BandwidthForPeer(context.Context, peer.ID) (metrics.Stats, error) {
+	return metrics.Stats{}, nil
+}
+
+func (m *p2pModule) BandwidthForProtocol(context.Context, protocol.ID) (metrics.Stats, error) {
+	return metrics.Stats{}, nil
+}
+
+func (m *p2pModule) ResourceState(context.Context) (rcmgr.ResourceManagerStat, error) {
+	return rcmgr.ResourceManagerStat{}, nil
+}
+
+func (m *p2pModule) PubSubPeers(context.Context, string) ([]peer.ID, error) {
+	return []peer.ID{}, nil
+}
@@ -0,0 +1,81 @@
+package testnode
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/celestiaorg/nmt"
+	"github.com/celestiaorg/rsmt2d"
+
+	"github.com/celestiaorg/celestia-openrpc/types/header"
+	"github.com/celestiaorg/celestia-openrpc/types/share"
+)
+
+// namespacedRow is a share.NamespacedRow as encoded by celestia-node, whose
+// shares are raw bytes.
+type namespacedRow struct {
+	Shares [][]byte   `json:"shares"`
+	Proof  *nmt.Proof `json:"proof"`
+}
+
+type shareModule struct {
+	n *Node
+}
+
+func (m *shareModule) SharesAvailable(_ context.Context, eh *header.ExtendedHeader) error {
+	_, err := m.n.blockOf(eh)
+	return err
+}
+
+func (m *shareModule) GetShare(_ context.Context, eh *header.ExtendedHeader, row, col int) ([]byte, error) {
+	b, err := m.n.blockOf(eh)
+	if err != nil {
+		return nil, err
+	}
+	width := int(b.eds.Width())
+	if row < 0 || row >= width || col < 0 || col >= width {
+		return nil, fmt.Errorf("share: coordinates (%d, %d) are outside of the %dx%d square", row, col, width, width)
+	}
+	return b.eds.GetCell(uint(row), uint(col)), nil
+}
+
+func (m *shareModule) GetEDS(_ context.Context, eh *header.ExtendedHeader) (*rsmt2d.ExtendedDataSquare, error) {
+	b, err := m.n.blockOf(eh)
+	if err != nil {
+		return nil, err
+	}
+	return b.eds, nil
+}
+
+func (m *shareModule) GetSharesByNamespace(
+	_ context.Context,
+	eh *header.ExtendedHeader,
+	ns share.Namespace,
+) ([]namespacedRow, error) {
+	if err := ns.ValidateForData(); err != nil {
+		return nil, err
+	}
+	b, err := m.n.blockOf(eh)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []namespacedRow
+	width := b.eds.Width() / 2
+	for row := uint(0); row < width; row++ {
+		root := b.header.DAH.RowRoots[row]
+		if ns.IsOutsideRange(root, root) {
+			continue
+		}
+		proof, err := namespaceProof(b.eds, row, ns)
+		if err != nil {
+			return nil, err
+		}
+		nsRow := namespacedRow{Proof: proof}
+		if !proof.IsOfAbsence() {
+			nsRow.Shares = b.eds.Row(row)[proof.Start():proof.End()]
+		}
+		rows = append(rows, nsRow)
+	}
+	return rows, nil
+}
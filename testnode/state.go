@@ -0,0 +1,178 @@
+package testnode
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+
+	"cosmossdk.io/math"
+
+	"github.com/celestiaorg/celestia-openrpc/types/blob"
+	"github.com/celestiaorg/celestia-openrpc/types/state"
+)
+
+var errUnsupported = errors.New("testnode: method is not supported")
+
+// charge deducts amount from the balance of addr.
+func (n *Node) charge(addr state.AccAddress, amount math.Int) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.chargeLocked(addr, amount)
+}
+
+func (n *Node) chargeLocked(addr state.AccAddress, amount math.Int) error {
+	balance := n.balance(addr)
+	if balance.LT(amount) {
+		return fmt.Errorf("spendable balance %s%s is smaller than %s%s: insufficient funds",
+			balance, Denom, amount, Denom)
+	}
+	n.balances[string(addr)] = balance.Sub(amount)
+	return nil
+}
+
+func (n *Node) balance(addr state.AccAddress) math.Int {
+	if balance, ok := n.balances[string(addr)]; ok {
+		return balance
+	}
+	return math.ZeroInt()
+}
+
+// txResponse returns the response for a tx included at height.
+func txResponse(tx []byte, height uint64, gasLimit uint64) *state.TxResponse {
+	return &state.TxResponse{
+		Height:    int64(height),
+		TxHash:    fmt.Sprintf("%X", sha256.Sum256(tx)),
+		GasWanted: int64(gasLimit),
+		GasUsed:   int64(gasLimit),
+	}
+}
+
+type stateModule struct {
+	n *Node
+}
+
+func (m *stateModule) AccountAddress(context.Context) (state.AccAddress, error) {
+	return m.n.account, nil
+}
+
+func (m *stateModule) Balance(ctx context.Context) (*state.Balance, error) {
+	return m.BalanceForAddress(ctx, m.n.account)
+}
+
+func (m *stateModule) BalanceForAddress(_ context.Context, addr state.AccAddress) (*state.Balance, error) {
+	m.n.mu.Lock()
+	defer m.n.mu.Unlock()
+	return &state.Balance{Denom: Denom, Amount: m.n.balance(addr)}, nil
+}
+
+func (m *stateModule) Transfer(
+	ctx context.Context,
+	to state.AccAddress,
+	amount,
+	fee state.Int,
+	gasLimit uint64,
+) (*state.TxResponse, error) {
+	if !amount.IsPositive() {
+		return nil, fmt.Errorf("invalid coins: %s%s", amount, Denom)
+	}
+
+	m.n.mu.Lock()
+	err := m.n.chargeLocked(m.n.account, amount.Add(fee))
+	if err == nil {
+		m.n.balances[string(to)] = m.n.balance(to).Add(amount)
+	}
+	m.n.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	tx := []byte(fmt.Sprintf("transfer %X %X %s", m.n.account, to, amount))
+	height, err := m.n.submit(ctx, tx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return txResponse(tx, height, gasLimit), nil
+}
+
+func (m *stateModule) SubmitTx(ctx context.Context, tx state.Tx) (*state.TxResponse, error) {
+	height, err := m.n.submit(ctx, tx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return txResponse(tx, height, 0), nil
+}
+
+func (m *stateModule) SubmitPayForBlob(
+	ctx context.Context,
+	fee state.Int,
+	gasLim uint64,
+	blobs []*blob.Blob,
+) (*state.TxResponse, error) {
+	if len(blobs) == 0 {
+		return nil, errors.New("testnode: no blobs to submit")
+	}
+	if err := m.n.charge(m.n.account, fee); err != nil {
+		return nil, err
+	}
+	tx := pfbTx(blobs)
+	height, err := m.n.submit(ctx, tx, blobs)
+	if err != nil {
+		return nil, err
+	}
+	return txResponse(tx, height, gasLim), nil
+}
+
+func (m *stateModule) CancelUnbondingDelegation(
+	context.Context, state.ValAddress, state.Int, state.Int, state.Int, uint64,
+) (*state.TxResponse, error) {
+	return nil, errUnsupported
+}
+
+func (m *stateModule) BeginRedelegate(
+	context.Context, state.ValAddress, state.ValAddress, state.Int, state.Int, uint64,
+) (*state.TxResponse, error) {
+	return nil, errUnsupported
+}
+
+func (m *stateModule) Undelegate(
+	context.Context, state.ValAddress, state.Int, state.Int, uint64,
+) (*state.TxResponse, error) {
+	return nil, errUnsupported
+}
+
+func (m *stateModule) Delegate(
+	context.Context, state.ValAddress, state.Int, state.Int, uint64,
+) (*state.TxResponse, error) {
+	return nil, errUnsupported
+}
+
+func (m *stateModule) QueryDelegation(
+	context.Context, state.ValAddress,
+) (*state.QueryDelegationResponse, error) {
+	return nil, errUnsupported
+}
+
+func (m *stateModule) QueryUnbonding(
+	context.Context, state.ValAddress,
+) (*state.QueryUnbondingDelegationResponse, error) {
+	return nil, errUnsupported
+}
+
+func (m *stateModule) QueryRedelegations(
+	context.Context, state.ValAddress, state.ValAddress,
+) (*state.QueryRedelegationsResponse, error) {
+	return nil, errUnsupported
+}
+
+func (m *stateModule) GrantFee(
+	context.Context, state.AccAddress, state.Int, state.Int, uint64,
+) (*state.TxResponse, error) {
+	return nil, errUnsupported
+}
+
+func (m *stateModule) RevokeGrantFee(
+	context.Context, state.AccAddress, state.Int, uint64,
+) (*state.TxResponse, error) {
+	return nil, errUnsupported
+}
@@ -0,0 +1,225 @@
+// Package testnode serves an in-memory fake of a celestia node over JSON-RPC,
+// so that clients can be tested without running a node:
+//
+//	node, err := testnode.New(testnode.WithBlockTime(100 * time.Millisecond))
+//	if err != nil {
+//		return err
+//	}
+//	defer node.Close()
+//
+//	c, err := client.NewClient(ctx, node.URL(), "")
+//
+// The node produces blocks holding the submitted blobs and transactions, laid
+// out in a data square from which real DataAvailabilityHeaders are computed.
+// It keeps the balances of accounts and accepts any token. Methods without an
+// in-memory equivalent, such as those of the staking module, return an error.
+package testnode
+
+import (
+	"crypto/sha256"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"cosmossdk.io/math"
+	gofraud "github.com/celestiaorg/go-fraud"
+	"github.com/filecoin-project/go-jsonrpc"
+
+	"github.com/celestiaorg/celestia-openrpc/types/fraud"
+	"github.com/celestiaorg/celestia-openrpc/types/header"
+	"github.com/celestiaorg/celestia-openrpc/types/state"
+)
+
+// APIVersion is the API version reported by Node.Info.
+const APIVersion = "testnode"
+
+// Denom is the denomination of balances.
+const Denom = "utia"
+
+// Option configures a Node.
+type Option func(*config)
+
+type config struct {
+	chainID   string
+	blockTime time.Duration
+	balance   math.Int
+}
+
+func defaultConfig() config {
+	return config{
+		chainID: "private",
+		balance: math.NewInt(1_000_000_000_000),
+	}
+}
+
+// WithChainID sets the chain ID of the headers. Defaults to "private".
+func WithChainID(chainID string) Option {
+	return func(cfg *config) {
+		cfg.chainID = chainID
+	}
+}
+
+// WithBlockTime produces a block every d. By default, blocks are only
+// produced on submissions and by ProduceBlock.
+func WithBlockTime(d time.Duration) Option {
+	return func(cfg *config) {
+		cfg.blockTime = d
+	}
+}
+
+// WithBalance sets the initial balance of the node's account.
+func WithBalance(amount math.Int) Option {
+	return func(cfg *config) {
+		cfg.balance = amount
+	}
+}
+
+// Node is a fake celestia node serving every module of the API.
+type Node struct {
+	cfg     config
+	srv     *httptest.Server
+	account state.AccAddress
+	started time.Time
+
+	mu     sync.Mutex
+	blocks []*block
+	// newBlock is closed and replaced when a block is produced.
+	newBlock chan struct{}
+	pending  []*pendingTx
+	balances map[string]math.Int
+	// fraudProofs are the published fraud proofs by type, and newFraudProof
+	// is closed and replaced when one is published.
+	fraudProofs   map[gofraud.ProofType][]*fraud.Proof
+	newFraudProof chan struct{}
+
+	connsMu sync.Mutex
+	conns   map[net.Conn]struct{}
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+// New starts a node with the genesis block at height 1.
+func New(opts ...Option) (*Node, error) {
+	cfg := defaultConfig()
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	addr := sha256.Sum256([]byte(cfg.chainID))
+	n := &Node{
+		cfg:      cfg,
+		account:  state.AccAddress(addr[:20]),
+		started:  time.Now().UTC(),
+		newBlock: make(chan struct{}),
+		balances: map[string]math.Int{},
+
+		fraudProofs:   map[gofraud.ProofType][]*fraud.Proof{},
+		newFraudProof: make(chan struct{}),
+
+		conns: map[net.Conn]struct{}{},
+		done:  make(chan struct{}),
+	}
+	n.balances[string(n.account)] = cfg.balance
+	if _, err := n.ProduceBlock(); err != nil {
+		return nil, err
+	}
+
+	p2p, err := newP2PModule()
+	if err != nil {
+		return nil, err
+	}
+	rpc := jsonrpc.NewServer()
+	rpc.Register("blob", &blobModule{n})
+	rpc.Register("header", &headerModule{n})
+	rpc.Register("state", &stateModule{n})
+	rpc.Register("share", &shareModule{n})
+	rpc.Register("das", &dasModule{n})
+	rpc.Register("p2p", p2p)
+	rpc.Register("node", &nodeModule{})
+	rpc.Register("fraud", &fraudModule{n})
+	rpc.Register("da", &daModule{n})
+	n.srv = httptest.NewUnstartedServer(rpc)
+	// websocket connections are hijacked, and so not closed by the server
+	n.srv.Config.ConnState = n.trackConn
+	n.srv.Start()
+
+	if cfg.blockTime > 0 {
+		n.wg.Add(1)
+		go n.produceBlocks()
+	}
+	return n, nil
+}
+
+// URL returns the websocket address of the node.
+func (n *Node) URL() string {
+	return "ws" + strings.TrimPrefix(n.srv.URL, "http")
+}
+
+// Close stops producing blocks and shuts the server down.
+func (n *Node) Close() {
+	close(n.done)
+	n.wg.Wait()
+	n.srv.CloseClientConnections()
+	n.srv.Close()
+}
+
+// DropConnections closes the connections of all clients, ending their
+// subscriptions.
+func (n *Node) DropConnections() {
+	n.connsMu.Lock()
+	defer n.connsMu.Unlock()
+	for conn := range n.conns {
+		conn.Close()
+		delete(n.conns, conn)
+	}
+}
+
+func (n *Node) trackConn(conn net.Conn, state http.ConnState) {
+	n.connsMu.Lock()
+	defer n.connsMu.Unlock()
+	switch state {
+	case http.StateNew:
+		n.conns[conn] = struct{}{}
+	case http.StateClosed:
+		delete(n.conns, conn)
+	}
+}
+
+// Account returns the address of the node's account, which pays for the
+// submitted blobs and transfers.
+func (n *Node) Account() state.AccAddress {
+	return n.account
+}
+
+// SetBalance sets the balance of an account.
+func (n *Node) SetBalance(addr state.AccAddress, amount math.Int) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.balances[string(addr)] = amount
+}
+
+// Head returns the header of the latest block.
+func (n *Node) Head() *header.ExtendedHeader {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.blocks[len(n.blocks)-1].header
+}
+
+func (n *Node) produceBlocks() {
+	defer n.wg.Done()
+	ticker := time.NewTicker(n.cfg.blockTime)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			// building a block only fails on broken invariants of the square
+			_, _ = n.ProduceBlock()
+		case <-n.done:
+			return
+		}
+	}
+}
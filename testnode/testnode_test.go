@@ -0,0 +1,152 @@
+package testnode_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"cosmossdk.io/math"
+	"github.com/stretchr/testify/require"
+
+	client "github.com/celestiaorg/celestia-openrpc"
+	"github.com/celestiaorg/celestia-openrpc/testnode"
+	"github.com/celestiaorg/celestia-openrpc/types/blob"
+	"github.com/celestiaorg/celestia-openrpc/types/header"
+	"github.com/celestiaorg/celestia-openrpc/types/share"
+	"github.com/celestiaorg/celestia-openrpc/types/state"
+)
+
+func newClient(t *testing.T, opts ...testnode.Option) (*testnode.Node, *client.Client) {
+	t.Helper()
+	// the connection lives as long as the context it is dialed with
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	node, err := testnode.New(opts...)
+	require.NoError(t, err)
+	t.Cleanup(node.Close)
+
+	c, err := client.NewClient(ctx, node.URL(), "")
+	require.NoError(t, err)
+	t.Cleanup(c.Close)
+	return node, c
+}
+
+func TestBlob(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_, c := newClient(t)
+
+	ns, err := share.NewBlobNamespaceV0([]byte("testnode"))
+	require.NoError(t, err)
+	small, err := blob.NewBlobV0(ns, []byte("hello"))
+	require.NoError(t, err)
+	// spans several rows of the square
+	large, err := blob.NewBlobV0(ns, make([]byte, 40_000))
+	require.NoError(t, err)
+
+	height, err := c.Blob.Submit(ctx, []*blob.Blob{small, large}, blob.DefaultGasPrice())
+	require.NoError(t, err)
+	require.EqualValues(t, 2, height)
+
+	got, err := c.Blob.Get(ctx, height, ns, large.Commitment)
+	require.NoError(t, err)
+	require.Equal(t, large.Data, got.Data)
+	require.Positive(t, got.Index())
+
+	all, err := c.Blob.GetAll(ctx, height, []share.Namespace{ns})
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+
+	for _, b := range []*blob.Blob{small, large} {
+		proof, err := c.Blob.GetProof(ctx, height, ns, b.Commitment)
+		require.NoError(t, err)
+		included, err := c.Blob.Included(ctx, height, ns, proof, b.Commitment)
+		require.NoError(t, err)
+		require.True(t, included)
+	}
+	proof, err := c.Blob.GetProof(ctx, height, ns, small.Commitment)
+	require.NoError(t, err)
+	included, err := c.Blob.Included(ctx, height, ns, proof, large.Commitment)
+	require.NoError(t, err)
+	require.False(t, included)
+
+	_, err = c.Blob.Get(ctx, height-1, ns, small.Commitment)
+	require.ErrorIs(t, err, blob.ErrBlobNotFound)
+}
+
+func TestHeader(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	node, c := newClient(t, testnode.WithBlockTime(10*time.Millisecond))
+
+	sub, err := c.Header.Subscribe(ctx)
+	require.NoError(t, err)
+	first := <-sub
+	second := <-sub
+	require.Equal(t, first.Height()+1, second.Height())
+	require.Equal(t, first.Hash(), second.LastHeader())
+
+	h, err := c.Header.GetByHeight(ctx, first.Height())
+	require.NoError(t, err)
+	require.Equal(t, first.Hash(), h.Hash())
+	h, err = c.Header.GetByHash(ctx, second.Hash())
+	require.NoError(t, err)
+	require.Equal(t, second.Height(), h.Height())
+
+	require.Equal(t, []byte(h.DataHash), h.DAH.Hash())
+	eds, err := c.Share.GetEDS(ctx, h)
+	require.NoError(t, err)
+	require.Len(t, h.DAH.RowRoots, int(eds.Width()))
+
+	_, err = c.Header.GetByHeight(ctx, node.Head().Height()+100)
+	require.ErrorIs(t, err, header.ErrHeightFromFuture)
+	var future *header.HeightFromFutureError
+	require.ErrorAs(t, err, &future)
+	require.Equal(t, node.Head().Height()+100, future.RequestedHeight)
+	_, err = c.Header.GetByHash(ctx, make([]byte, 32))
+	require.ErrorIs(t, err, header.ErrNotFound)
+}
+
+func TestState(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	node, c := newClient(t, testnode.WithBalance(math.NewInt(1000)))
+
+	to := state.AccAddress("recipient")
+	resp, err := c.State.Transfer(ctx, to, math.NewInt(300), math.NewInt(100), 100_000)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, resp.Height)
+
+	balance, err := c.State.Balance(ctx)
+	require.NoError(t, err)
+	require.Equal(t, math.NewInt(600), balance.Amount)
+	require.Equal(t, testnode.Denom, balance.Denom)
+
+	_, err = c.State.Transfer(ctx, to, math.NewInt(600), math.NewInt(100), 100_000)
+	require.ErrorIs(t, err, state.ErrInsufficientFunds)
+	require.ErrorContains(t, err, "spendable balance")
+
+	node.SetBalance(node.Account(), math.NewInt(0))
+	ns, err := share.NewBlobNamespaceV0([]byte("testnode"))
+	require.NoError(t, err)
+	b, err := blob.NewBlobV0(ns, []byte("hello"))
+	require.NoError(t, err)
+	_, err = c.Blob.Submit(ctx, []*blob.Blob{b}, blob.DefaultGasPrice())
+	require.ErrorIs(t, err, state.ErrInsufficientFunds)
+}
+
+func TestWaitReady(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_, c := newClient(t)
+
+	var stages []client.ReadyStage
+	policy := client.DASCaughtUp()
+	policy.OnProgress = func(p client.ReadyProgress) {
+		require.NoError(t, p.Err)
+		stages = append(stages, p.Stage)
+	}
+	require.NoError(t, c.WaitReady(ctx, policy))
+	require.Equal(t, []client.ReadyStage{client.ReadyRPC, client.ReadyHeaderSync, client.ReadyDAS, client.ReadyDone}, stages)
+}
@@ -5,9 +5,11 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"reflect"
 
 	"github.com/celestiaorg/nmt"
 
+	"github.com/celestiaorg/celestia-openrpc/internal/blobindex"
 	"github.com/celestiaorg/celestia-openrpc/types/appconsts"
 	"github.com/celestiaorg/celestia-openrpc/types/share"
 
@@ -24,6 +26,7 @@ const (
 )
 
 var (
+	// ErrBlobNotFound is returned when the blob is not at the given height.
 	ErrBlobNotFound = errors.New("blob: not found")
 	ErrInvalidProof = errors.New("blob: invalid proof")
 )
@@ -101,6 +104,12 @@ func DefaultGasPrice() float64 {
 	return -1.0
 }
 
+func init() {
+	blobindex.Set = func(b interface{}, index int) {
+		b.(*Blob).index = index
+	}
+}
+
 type jsonBlob struct {
 	Namespace    share.Namespace `json:"namespace"`
 	Data         []byte          `json:"data"`
@@ -144,3 +153,9 @@ func (b *Blob) UnmarshalJSON(data []byte) error {
 func (b *Blob) Index() int {
 	return b.index
 }
+
+// WireType returns the type Blob is encoded as in JSON, to describe it in
+// OpenRPC documents.
+func WireType() reflect.Type {
+	return reflect.TypeOf(jsonBlob{})
+}
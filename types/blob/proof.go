@@ -0,0 +1,167 @@
+package blob
+
+import (
+	"fmt"
+
+	"github.com/celestiaorg/nmt"
+
+	"github.com/celestiaorg/celestia-openrpc/types/appconsts"
+	"github.com/celestiaorg/celestia-openrpc/types/header"
+	"github.com/celestiaorg/celestia-openrpc/types/namespace"
+	"github.com/celestiaorg/celestia-openrpc/types/share"
+
+	"github.com/celestiaorg/go-square/inclusion"
+	"github.com/celestiaorg/go-square/merkle"
+)
+
+// VerifyInclusion verifies, without asking the node, that proof proves the
+// blob of namespace ns and commitment com to be included in the square of
+// dah.
+//
+// As celestia-node does, each nmt.Proof proves all the shares of ns in a row
+// the blob spans, not only those of the blob. The proof only holds the nodes
+// outside of these shares, so they are taken from rows, the shares of ns in
+// the square as returned by the share module's GetSharesByNamespace. Each
+// nmt.Proof is checked against the row root of the row it covers, and the
+// blob is then looked up among the proven shares, its commitment recomputed
+// from the subtree roots of its shares.
+//
+// It returns an error wrapping ErrInvalidProof if the proof does not hold.
+func VerifyInclusion(
+	dah *header.DataAvailabilityHeader,
+	ns share.Namespace,
+	proof *Proof,
+	com Commitment,
+	rows share.NamespacedShares,
+) error {
+	switch {
+	case dah == nil || len(dah.RowRoots) == 0:
+		return fmt.Errorf("%w: empty data availability header", ErrInvalidProof)
+	case proof == nil || proof.Len() == 0:
+		return fmt.Errorf("%w: empty proof", ErrInvalidProof)
+	}
+
+	// the rows of the original data square, the only ones holding blobs
+	width := len(dah.RowRoots) / 2
+	if err := checkRanges(*proof, width); err != nil {
+		return err
+	}
+	// the rows of the namespace, in the order of rows
+	var nsRows []int
+	for row, root := range dah.RowRoots[:width] {
+		if len(root) >= 2*appconsts.NamespaceSize && !ns.IsOutsideRange(root, root) {
+			nsRows = append(nsRows, row)
+		}
+	}
+	if len(nsRows) != len(rows) {
+		return fmt.Errorf("%w: namespace spans %d rows, but %d rows of shares are given",
+			ErrInvalidProof, len(nsRows), len(rows))
+	}
+
+	// the proof does not tell the row it starts in, so try all rows of the
+	// namespace
+	for first := 0; first+proof.Len() <= len(rows); first++ {
+		shares, ok := verifyRows(dah.RowRoots, nsRows[first:], ns, *proof, rows[first:])
+		if !ok {
+			continue
+		}
+		firstRow, lastRow := (*proof)[0], (*proof)[proof.Len()-1]
+		lastRowStart := len(shares) - (lastRow.End() - lastRow.Start())
+		return findBlob(ns, com, shares, firstRow.End()-firstRow.Start(), lastRowStart)
+	}
+	return fmt.Errorf("%w: shares are not included in the row roots", ErrInvalidProof)
+}
+
+// subtreeRoots returns the roots of the subtrees of the shares of a blob,
+// which its commitment is the merkle root of.
+func subtreeRoots(ns share.Namespace, shares [][]byte) ([][]byte, error) {
+	width := share.SubTreeWidth(len(shares), appconsts.DefaultSubtreeRootThreshold)
+	sizes, err := inclusion.MerkleMountainRangeSizes(uint64(len(shares)), uint64(width))
+	if err != nil {
+		return nil, err
+	}
+	roots := make([][]byte, 0, len(sizes))
+	for _, size := range sizes {
+		tree := nmt.New(namespace.NewBaseHashFunc(),
+			nmt.NamespaceIDSize(appconsts.NamespaceSize),
+			nmt.IgnoreMaxNamespace(NMTIgnoreMaxNamespace),
+		)
+		for _, sh := range shares[:size] {
+			if err := tree.Push(append(append([]byte{}, ns...), sh...)); err != nil {
+				return nil, err
+			}
+		}
+		root, err := tree.Root()
+		if err != nil {
+			return nil, err
+		}
+		roots = append(roots, root)
+		shares = shares[size:]
+	}
+	return roots, nil
+}
+
+// checkRanges checks that each proof covers consecutive shares of
+// consecutive rows of the given width.
+func checkRanges(proof Proof, width int) error {
+	for i, p := range proof {
+		switch {
+		case p == nil || p.Start() < 0 || p.Start() >= p.End() || p.End() > width:
+			return fmt.Errorf("%w: proof %d is out of the row", ErrInvalidProof, i)
+		case i > 0 && p.Start() != 0, i < len(proof)-1 && p.End() != width:
+			return fmt.Errorf("%w: proof %d does not continue the previous one", ErrInvalidProof, i)
+		}
+	}
+	return nil
+}
+
+// verifyRows reports whether each proof proves the shares of the row at the
+// same position against its row root, returning the proven shares.
+func verifyRows(
+	rowRoots [][]byte,
+	rowIndexes []int,
+	ns share.Namespace,
+	proof Proof,
+	rows share.NamespacedShares,
+) ([]share.Share, bool) {
+	var proven []share.Share
+	for i, p := range proof {
+		if rowIndexes[i] != rowIndexes[0]+i || len(rows[i].Shares) != p.End()-p.Start() {
+			return nil, false
+		}
+		leaves := share.ToBytes(rows[i].Shares)
+		if !p.VerifyInclusion(namespace.NewBaseHashFunc(), ns.ToNMT(), leaves, rowRoots[rowIndexes[i]]) {
+			return nil, false
+		}
+		proven = append(proven, rows[i].Shares...)
+	}
+	return proven, true
+}
+
+// findBlob looks up the blob of commitment com among the proven shares of
+// namespace ns. As the proof covers the rows the blob spans, the blob must
+// start in the first firstRowLen shares and end after lastRowStart.
+func findBlob(ns share.Namespace, com Commitment, shares []share.Share, firstRowLen, lastRowStart int) error {
+	for i := 0; i < firstRowLen; i++ {
+		start, err := shares[i].IsSequenceStart()
+		if err != nil || !start {
+			continue
+		}
+		sequenceLen, err := shares[i].SequenceLen()
+		if err != nil {
+			continue
+		}
+		end := i + share.SparseSharesNeeded(sequenceLen)
+		if end == i || end <= lastRowStart || end > len(shares) {
+			continue
+		}
+		roots, err := subtreeRoots(ns, share.ToBytes(shares[i:end]))
+		if err != nil {
+			return err
+		}
+		if com.Equal(merkle.HashFromByteSlices(roots)) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: no blob of the commitment spans the proven rows", ErrInvalidProof)
+}
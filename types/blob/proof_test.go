@@ -0,0 +1,114 @@
+package blob_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/celestiaorg/nmt"
+	"github.com/stretchr/testify/require"
+
+	client "github.com/celestiaorg/celestia-openrpc"
+	"github.com/celestiaorg/celestia-openrpc/testnode"
+	"github.com/celestiaorg/celestia-openrpc/types/blob"
+	"github.com/celestiaorg/celestia-openrpc/types/share"
+)
+
+func TestVerifyInclusion(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	node, err := testnode.New()
+	require.NoError(t, err)
+	defer node.Close()
+	c, err := client.NewClient(ctx, node.URL(), "")
+	require.NoError(t, err)
+	defer c.Close()
+
+	ns, err := share.NewBlobNamespaceV0([]byte("verify"))
+	require.NoError(t, err)
+	other, err := share.NewBlobNamespaceV0([]byte("other"))
+	require.NoError(t, err)
+	small, err := blob.NewBlobV0(ns, []byte("hello"))
+	require.NoError(t, err)
+	// spans several rows of the square
+	large, err := blob.NewBlobV0(ns, make([]byte, 40_000))
+	require.NoError(t, err)
+	otherBlob, err := blob.NewBlobV0(other, []byte("hello"))
+	require.NoError(t, err)
+	height, err := c.Blob.Submit(ctx, []*blob.Blob{small, large, otherBlob}, blob.DefaultGasPrice())
+	require.NoError(t, err)
+	h, err := c.Header.GetByHeight(ctx, height)
+	require.NoError(t, err)
+
+	rows, err := c.Share.GetSharesByNamespace(ctx, h, ns)
+	require.NoError(t, err)
+	for _, b := range []*blob.Blob{small, large} {
+		proof, err := c.Blob.GetProof(ctx, height, ns, b.Commitment)
+		require.NoError(t, err)
+		require.NoError(t, blob.VerifyInclusion(h.DAH, ns, proof, b.Commitment, rows))
+	}
+
+	proof, err := c.Blob.GetProof(ctx, height, ns, large.Commitment)
+	require.NoError(t, err)
+	smallProof, err := c.Blob.GetProof(ctx, height, ns, small.Commitment)
+	require.NoError(t, err)
+	// the row of the small blob holds the first shares of the large one
+	// too, which its proof covers
+	require.Len(t, *smallProof, 1)
+	require.Greater(t, (*smallProof)[0].End()-(*smallProof)[0].Start(), 1)
+	require.Equal(t, (*smallProof)[0].Start(), (*proof)[0].Start())
+
+	otherProof, err := c.Blob.GetProof(ctx, height, other, otherBlob.Commitment)
+	require.NoError(t, err)
+	otherRows, err := c.Share.GetSharesByNamespace(ctx, h, other)
+	require.NoError(t, err)
+	proofRows := *proof
+	require.Greater(t, len(proofRows), 1)
+	reversed := append(blob.Proof{proofRows[len(proofRows)-1]}, proofRows[:len(proofRows)-1]...)
+
+	// the last share of the first row, which belongs to the large blob
+	tampered := make(share.NamespacedShares, len(rows))
+	copy(tampered, rows)
+	last := len(rows[0].Shares) - 1
+	tampered[0].Shares = append([]share.Share{}, rows[0].Shares...)
+	data := append([]byte{}, tampered[0].Shares[last].ToBytes()...)
+	data[len(data)-1]++
+	sh, err := share.NewShare(data)
+	require.NoError(t, err)
+	tampered[0].Shares[last] = *sh
+
+	tests := []struct {
+		name  string
+		ns    share.Namespace
+		proof *blob.Proof
+		com   blob.Commitment
+		rows  share.NamespacedShares
+	}{
+		{"wrong commitment", ns, proof, otherBlob.Commitment, rows},
+		{"blob outside of the proven rows", ns, proof, small.Commitment, rows},
+		{"tampered shares", ns, proof, large.Commitment, tampered},
+		{"wrong namespace", other, proof, large.Commitment, rows},
+		{"shares of another namespace", ns, proof, large.Commitment, otherRows},
+		{"missing shares", ns, proof, large.Commitment, rows[1:]},
+		{"proof of another blob", ns, smallProof, large.Commitment, rows},
+		{"proof of another namespace", ns, otherProof, small.Commitment, rows},
+		{"reordered rows", ns, &reversed, large.Commitment, rows},
+		{"missing row", ns, &blob.Proof{proofRows[0]}, large.Commitment, rows},
+		{"no proof", ns, &blob.Proof{}, large.Commitment, rows},
+		{"nil row", ns, &blob.Proof{nil}, small.Commitment, rows},
+		{"empty row", ns, &blob.Proof{&nmt.Proof{}}, small.Commitment, rows},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := blob.VerifyInclusion(h.DAH, tt.ns, tt.proof, tt.com, tt.rows)
+			require.ErrorIs(t, err, blob.ErrInvalidProof)
+		})
+	}
+
+	// the proof does not hold against another square
+	empty, err := node.ProduceBlock()
+	require.NoError(t, err)
+	err = blob.VerifyInclusion(empty.DAH, ns, smallProof, small.Commitment, rows)
+	require.ErrorIs(t, err, blob.ErrInvalidProof)
+}
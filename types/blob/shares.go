@@ -0,0 +1,112 @@
+package blob
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/celestiaorg/celestia-openrpc/types/share"
+)
+
+// ToShares splits b into the shares it takes up in a data square.
+func ToShares(b *Blob) ([]share.Share, error) {
+	if b == nil {
+		return nil, errors.New("blob: nil blob")
+	}
+	splitter := share.NewSparseShareSplitter()
+	if err := splitter.Write(b.ShareVersion, b.Namespace().Bytes(), b.Data); err != nil {
+		return nil, err
+	}
+	return splitter.Export(), nil
+}
+
+// FromShares reconstructs the blobs from consecutive shares, such as those of
+// a namespace or of a whole original data square in row-major order. Padding
+// shares and the compact shares of reserved namespaces are skipped.
+//
+// The shares start at index start of an original data square of width width,
+// from which the index of each blob in the extended data square is computed,
+// as the node reports it. Without a width, such as for the shares of a
+// namespace, the index of the blobs is left at -1.
+func FromShares(shares []share.Share, width, start int) ([]*Blob, error) {
+	var (
+		blobs []*Blob
+		// the blob being read, if any
+		cur *partialBlob
+	)
+	for i := range shares {
+		sh := &shares[i]
+		if err := sh.Validate(); err != nil {
+			return nil, fmt.Errorf("blob: share %d: %w", i, err)
+		}
+		ns, err := sh.Namespace()
+		if err != nil {
+			return nil, fmt.Errorf("blob: share %d: %w", i, err)
+		}
+		infoByte, err := sh.InfoByte()
+		if err != nil {
+			return nil, fmt.Errorf("blob: share %d: %w", i, err)
+		}
+
+		if cur != nil {
+			if infoByte.IsSequenceStart() || !cur.ns.Equals(ns.Bytes()) {
+				return nil, fmt.Errorf("blob: share %d: blob starting at share %d ends after %d of %d bytes",
+					i, cur.first, len(cur.data), cur.sequenceLen)
+			}
+		} else {
+			padding, err := sh.IsPadding()
+			if err != nil {
+				return nil, fmt.Errorf("blob: share %d: %w", i, err)
+			}
+			if padding || ns.IsReserved() {
+				continue
+			}
+			if !infoByte.IsSequenceStart() {
+				return nil, fmt.Errorf("blob: share %d continues a blob that does not start in the shares", i)
+			}
+			sequenceLen, err := sh.SequenceLen()
+			if err != nil {
+				return nil, fmt.Errorf("blob: share %d: %w", i, err)
+			}
+			cur = &partialBlob{
+				ns:          share.Namespace(ns.Bytes()),
+				version:     infoByte.Version(),
+				sequenceLen: int(sequenceLen),
+				first:       i,
+			}
+		}
+
+		data, err := sh.RawData()
+		if err != nil {
+			return nil, fmt.Errorf("blob: share %d: %w", i, err)
+		}
+		cur.data = append(cur.data, data...)
+		if len(cur.data) < cur.sequenceLen {
+			continue
+		}
+		b, err := NewBlob(cur.version, cur.ns, cur.data[:cur.sequenceLen])
+		if err != nil {
+			return nil, fmt.Errorf("blob: blob starting at share %d: %w", cur.first, err)
+		}
+		if width > 0 {
+			index := start + cur.first
+			b.index = index/width*2*width + index%width
+		}
+		blobs = append(blobs, b)
+		cur = nil
+	}
+	if cur != nil {
+		return nil, fmt.Errorf("blob: blob starting at share %d ends after %d of %d bytes",
+			cur.first, len(cur.data), cur.sequenceLen)
+	}
+	return blobs, nil
+}
+
+// partialBlob is a blob whose shares are being read.
+type partialBlob struct {
+	ns          share.Namespace
+	version     uint8
+	sequenceLen int
+	data        []byte
+	// first is the position of the first share of the blob in the shares.
+	first int
+}
@@ -0,0 +1,104 @@
+package blob_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	client "github.com/celestiaorg/celestia-openrpc"
+	"github.com/celestiaorg/celestia-openrpc/testnode"
+	"github.com/celestiaorg/celestia-openrpc/types/blob"
+	"github.com/celestiaorg/celestia-openrpc/types/share"
+)
+
+func TestToShares(t *testing.T) {
+	ns, err := share.NewBlobNamespaceV0([]byte("shares"))
+	require.NoError(t, err)
+	b, err := blob.NewBlobV0(ns, make([]byte, 2_000))
+	require.NoError(t, err)
+
+	shares, err := blob.ToShares(b)
+	require.NoError(t, err)
+	require.Len(t, shares, 5)
+
+	blobs, err := blob.FromShares(shares, 0, 0)
+	require.NoError(t, err)
+	require.Len(t, blobs, 1)
+	require.Equal(t, b.Data, blobs[0].Data)
+	require.Equal(t, b.Commitment, blobs[0].Commitment)
+	require.Equal(t, -1, blobs[0].Index())
+
+	// starting at the second share of the second row of a square 4 shares
+	// wide, which is 8 shares wide once extended
+	blobs, err = blob.FromShares(shares, 4, 5)
+	require.NoError(t, err)
+	require.Equal(t, 9, blobs[0].Index())
+
+	_, err = blob.FromShares(shares[:4], 0, 0)
+	require.ErrorContains(t, err, "ends after")
+	_, err = blob.FromShares(shares[1:], 0, 0)
+	require.ErrorContains(t, err, "does not start in the shares")
+	_, err = blob.FromShares(append(shares[:2:2], shares...), 0, 0)
+	require.ErrorContains(t, err, "ends after")
+}
+
+func TestFromShares(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	node, err := testnode.New()
+	require.NoError(t, err)
+	defer node.Close()
+	c, err := client.NewClient(ctx, node.URL(), "")
+	require.NoError(t, err)
+	defer c.Close()
+
+	ns, err := share.NewBlobNamespaceV0([]byte("shares"))
+	require.NoError(t, err)
+	other, err := share.NewBlobNamespaceV0([]byte("other"))
+	require.NoError(t, err)
+	var submitted []*blob.Blob
+	for _, data := range [][]byte{[]byte("hello"), make([]byte, 40_000)} {
+		for _, ns := range []share.Namespace{ns, other} {
+			b, err := blob.NewBlobV0(ns, data)
+			require.NoError(t, err)
+			submitted = append(submitted, b)
+		}
+	}
+	height, err := c.Blob.Submit(ctx, submitted, blob.DefaultGasPrice())
+	require.NoError(t, err)
+	h, err := c.Header.GetByHeight(ctx, height)
+	require.NoError(t, err)
+
+	// the whole original data square, including txs and padding
+	eds, err := c.Share.GetEDS(ctx, h)
+	require.NoError(t, err)
+	ods, err := share.FromBytes(eds.FlattenedODS())
+	require.NoError(t, err)
+	blobs, err := blob.FromShares(ods, int(eds.Width()/2), 0)
+	require.NoError(t, err)
+	require.Len(t, blobs, len(submitted))
+	for _, b := range blobs {
+		got, err := c.Blob.Get(ctx, height, b.Namespace().Bytes(), b.Commitment)
+		require.NoError(t, err)
+		require.Equal(t, got.Data, b.Data)
+		require.Equal(t, got.Index(), b.Index())
+	}
+
+	rows, err := c.Share.GetSharesByNamespace(ctx, h, ns)
+	require.NoError(t, err)
+	var shares []share.Share
+	for _, row := range rows {
+		shares = append(shares, row.Shares...)
+	}
+	blobs, err = blob.FromShares(shares, 0, 0)
+	require.NoError(t, err)
+	all, err := c.Blob.GetAll(ctx, height, []share.Namespace{ns})
+	require.NoError(t, err)
+	require.Len(t, blobs, len(all))
+	for i, b := range blobs {
+		require.Equal(t, all[i].Commitment, b.Commitment)
+	}
+}
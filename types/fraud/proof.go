@@ -1,11 +1,54 @@
 package fraud
 
 import (
+	"encoding/json"
+
 	"github.com/celestiaorg/celestia-openrpc/types/header"
 	"github.com/celestiaorg/go-fraud"
 )
 
+// Unmarshaler decodes the fraud proofs received from the node by their type.
+// The proofs of celestia-node are not part of this module, so their
+// unmarshalers have to be registered to receive them:
+//
+//	fraud.Unmarshaler.Unmarshalers[byzantine.BadEncoding] = unmarshalBEFP
+var Unmarshaler = fraud.MultiUnmarshaler[*header.ExtendedHeader]{
+	Unmarshalers: map[fraud.ProofType]func([]byte) (fraud.Proof[*header.ExtendedHeader], error){},
+}
+
 // Proof embeds the fraud.Proof interface type to provide a concrete type for JSON serialization.
 type Proof struct {
 	fraud.Proof[*header.ExtendedHeader]
 }
+
+type fraudProofJSON struct {
+	ProofType fraud.ProofType `json:"proof_type"`
+	Data      []byte          `json:"data"`
+}
+
+func (f *Proof) MarshalJSON() ([]byte, error) {
+	if f.Proof == nil {
+		return []byte("null"), nil
+	}
+	data, err := f.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(&fraudProofJSON{ProofType: f.Type(), Data: data})
+}
+
+func (f *Proof) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var fp fraudProofJSON
+	if err := json.Unmarshal(data, &fp); err != nil {
+		return err
+	}
+	proof, err := Unmarshaler.Unmarshal(fp.ProofType, fp.Data)
+	if err != nil {
+		return err
+	}
+	f.Proof = proof
+	return nil
+}
@@ -0,0 +1,60 @@
+package header
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	libhead "github.com/celestiaorg/go-header"
+)
+
+var (
+	// ErrNotFound is returned when the node has no header for the given hash
+	// or height. It matches go-header's ErrNotFound in errors.Is.
+	ErrNotFound error = notFoundError{}
+	// ErrHeightFromFuture is returned, as a *HeightFromFutureError, when the
+	// requested height is above the head of the network.
+	ErrHeightFromFuture = errors.New("header: given height is from the future")
+	// ErrNotSynced is returned when the node has not synced headers far
+	// enough to serve the request.
+	ErrNotSynced = errors.New("header: not synced")
+)
+
+// notFoundError is the type of ErrNotFound, which matches go-header's
+// ErrNotFound.
+type notFoundError struct{}
+
+func (notFoundError) Error() string {
+	return libhead.ErrNotFound.Error()
+}
+
+func (notFoundError) Is(target error) bool {
+	return target == libhead.ErrNotFound
+}
+
+// HeightFromFutureError reports a request for a height above the head of the
+// network.
+type HeightFromFutureError struct {
+	NetworkHeight   uint64 `json:"network_height"`
+	RequestedHeight uint64 `json:"requested_height"`
+}
+
+func (e *HeightFromFutureError) Error() string {
+	return fmt.Sprintf("%s: networkHeight: %d, requestedHeight: %d",
+		ErrHeightFromFuture, e.NetworkHeight, e.RequestedHeight)
+}
+
+func (e *HeightFromFutureError) Is(target error) bool {
+	return target == ErrHeightFromFuture
+}
+
+// ParseHeightFromFutureError parses the message of a HeightFromFutureError as
+// formatted by celestia-node. The heights are left zero if the message does
+// not carry them.
+func ParseHeightFromFutureError(msg string) *HeightFromFutureError {
+	e := &HeightFromFutureError{}
+	if i := strings.Index(msg, "networkHeight:"); i >= 0 {
+		_, _ = fmt.Sscanf(msg[i:], "networkHeight: %d, requestedHeight: %d", &e.NetworkHeight, &e.RequestedHeight)
+	}
+	return e
+}
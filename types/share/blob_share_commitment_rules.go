@@ -36,6 +36,30 @@ func SubTreeWidth(shareCount, subtreeRootThreshold int) int {
 	return min(s, BlobMinSquareSize(shareCount))
 }
 
+// NextShareIndex determines the next index in a square that can be used. It
+// follows the blob share commitment rules defined in ADR-013. Assumes that all
+// args are non negative, that squareSize is a power of two and that the blob can
+// fit in the square. The cursor is expected to be the index after the end of
+// the previous blob.
+func NextShareIndex(cursor, blobShareLen, subtreeRootThreshold int) int {
+	// Calculate the subtreewidth. This is the width of the first mountain in the
+	// merkle mountain range that makes up the blob share commitment (given the
+	// subtreeRootThreshold and the BlobMinSquareSize).
+	treeWidth := SubTreeWidth(blobShareLen, subtreeRootThreshold)
+	// Round up the cursor to the next multiple of treeWidth. For example, if
+	// the cursor was at 13 and the tree width is 4, return 16.
+	return RoundUpByMultipleOf(cursor, treeWidth)
+}
+
+// RoundUpByMultipleOf rounds cursor up to the next multiple of v. If cursor is divisible
+// by v, then it returns cursor.
+func RoundUpByMultipleOf(cursor, v int) int {
+	if cursor%v == 0 {
+		return cursor
+	}
+	return ((cursor / v) + 1) * v
+}
+
 func min[T constraints.Integer](i, j T) T {
 	if i < j {
 		return i
@@ -0,0 +1,106 @@
+package share_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	gsnamespace "github.com/celestiaorg/go-square/namespace"
+	gsshares "github.com/celestiaorg/go-square/shares"
+	"github.com/stretchr/testify/require"
+
+	client "github.com/celestiaorg/celestia-openrpc"
+	"github.com/celestiaorg/celestia-openrpc/testnode"
+	"github.com/celestiaorg/celestia-openrpc/types/appconsts"
+	"github.com/celestiaorg/celestia-openrpc/types/blob"
+	"github.com/celestiaorg/celestia-openrpc/types/namespace"
+	"github.com/celestiaorg/celestia-openrpc/types/share"
+)
+
+func TestCompactShareSplitter(t *testing.T) {
+	txs := [][]byte{
+		[]byte("small"),
+		bytes.Repeat([]byte{1}, 1_000),
+		bytes.Repeat([]byte{2}, appconsts.ContinuationCompactShareContentSize),
+		[]byte("last"),
+	}
+	splitter := share.NewCompactShareSplitter(namespace.TxNamespace, appconsts.ShareVersionZero)
+	reference := gsshares.NewCompactShareSplitter(gsnamespace.TxNamespace, appconsts.ShareVersionZero)
+	for _, tx := range txs {
+		require.NoError(t, splitter.WriteTx(tx))
+		require.NoError(t, reference.WriteTx(tx))
+	}
+	shares, err := splitter.Export()
+	require.NoError(t, err)
+	require.Equal(t, splitter.Count(), len(shares))
+	want, err := reference.Export()
+	require.NoError(t, err)
+	require.Equal(t, gsshares.ToBytes(want), share.ToBytes(shares))
+
+	units, err := share.ParseCompactShares(shares)
+	require.NoError(t, err)
+	require.Equal(t, txs, units)
+
+	// from the middle of the sequence, the unit cut off at the start is skipped
+	require.Len(t, shares, 4)
+	units, err = share.ParseCompactShares(shares[1:])
+	require.NoError(t, err)
+	require.Equal(t, txs[2:], units)
+	// and so is the one cut off at the end
+	units, err = share.ParseCompactShares(shares[1:3])
+	require.NoError(t, err)
+	require.Empty(t, units)
+
+	_, err = share.ParseCompactShares(shares[:len(shares)-1])
+	require.ErrorContains(t, err, "bytes of their sequence")
+	_, err = share.ParseCompactShares(append(shares[:1:1], shares...))
+	require.ErrorContains(t, err, "starts a second sequence")
+}
+
+func TestParseCompactShares(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	node, err := testnode.New()
+	require.NoError(t, err)
+	defer node.Close()
+	c, err := client.NewClient(ctx, node.URL(), "")
+	require.NoError(t, err)
+	defer c.Close()
+
+	tx := bytes.Repeat([]byte{1}, 1_500)
+	resp, err := c.State.SubmitTx(ctx, tx)
+	require.NoError(t, err)
+	h, err := c.Header.GetByHeight(ctx, uint64(resp.Height))
+	require.NoError(t, err)
+	rows, err := c.Share.GetSharesByNamespace(ctx, h, namespace.TxNamespace.Bytes())
+	require.NoError(t, err)
+	units, err := share.ParseCompactShares(flatten(rows))
+	require.NoError(t, err)
+	require.Equal(t, [][]byte{tx}, units)
+
+	ns, err := share.NewBlobNamespaceV0([]byte("compact"))
+	require.NoError(t, err)
+	b, err := blob.NewBlobV0(ns, []byte("hello"))
+	require.NoError(t, err)
+	height, err := c.Blob.Submit(ctx, []*blob.Blob{b}, blob.DefaultGasPrice())
+	require.NoError(t, err)
+	h, err = c.Header.GetByHeight(ctx, height)
+	require.NoError(t, err)
+	rows, err = c.Share.GetSharesByNamespace(ctx, h, namespace.PayForBlobNamespace.Bytes())
+	require.NoError(t, err)
+	units, err = share.ParseCompactShares(flatten(rows))
+	require.NoError(t, err)
+	require.Len(t, units, 1)
+	// the PFB is wrapped with the indexes of its blobs
+	require.True(t, bytes.Contains(units[0], b.Commitment))
+}
+
+func flatten(rows share.NamespacedShares) []share.Share {
+	var shares []share.Share
+	for _, row := range rows {
+		shares = append(shares, row.Shares...)
+	}
+	return shares
+}
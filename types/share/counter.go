@@ -0,0 +1,89 @@
+package share
+
+import (
+	"encoding/binary"
+
+	"github.com/celestiaorg/celestia-openrpc/types/appconsts"
+)
+
+// CompactShareCounter counts the compact shares a set of units, such as
+// transactions or PayForBlob transactions, will be split into, without
+// splitting them.
+type CompactShareCounter struct {
+	lastShares    int
+	lastRemainder int
+	shares        int
+	// remainder is the number of bytes used for data in the last share
+	remainder int
+}
+
+// NewCompactShareCounter returns an empty CompactShareCounter.
+func NewCompactShareCounter() *CompactShareCounter {
+	return &CompactShareCounter{}
+}
+
+// Add adds the length of a unit to the counter and returns the number of
+// shares the counter has been increased by.
+func (c *CompactShareCounter) Add(dataLen int) int {
+	// the unit is prefixed with its length as a varint
+	dataLen += DelimLen(uint64(dataLen))
+
+	c.lastRemainder = c.remainder
+	c.lastShares = c.shares
+
+	// the first share holds less data as it holds the sequence length
+	if c.shares == 0 {
+		if dataLen >= appconsts.FirstCompactShareContentSize-c.remainder {
+			dataLen -= appconsts.FirstCompactShareContentSize - c.remainder
+			c.shares++
+			c.remainder = 0
+		} else {
+			c.remainder += dataLen
+			dataLen = 0
+		}
+	}
+
+	// fill the rest of the last continuation share
+	if dataLen >= appconsts.ContinuationCompactShareContentSize-c.remainder {
+		dataLen -= appconsts.ContinuationCompactShareContentSize - c.remainder
+		c.shares++
+		c.remainder = 0
+	} else {
+		c.remainder += dataLen
+		dataLen = 0
+	}
+
+	if dataLen > 0 {
+		c.shares += dataLen / appconsts.ContinuationCompactShareContentSize
+		c.remainder = dataLen % appconsts.ContinuationCompactShareContentSize
+	}
+
+	diff := c.shares - c.lastShares
+	if c.lastRemainder == 0 && c.remainder > 0 {
+		diff++
+	} else if c.lastRemainder > 0 && c.remainder == 0 {
+		diff--
+	}
+	return diff
+}
+
+// Revert reverts the last call to Add. Only the last call can be reverted.
+func (c *CompactShareCounter) Revert() {
+	c.shares = c.lastShares
+	c.remainder = c.lastRemainder
+}
+
+// Size returns the number of shares counted.
+func (c *CompactShareCounter) Size() int {
+	if c.remainder == 0 {
+		return c.shares
+	}
+	return c.shares + 1
+}
+
+// DelimLen returns the number of bytes of the varint prefixing a unit of
+// length size.
+func DelimLen(size uint64) int {
+	lenBuf := make([]byte, binary.MaxVarintLen64)
+	return binary.PutUvarint(lenBuf, size)
+}
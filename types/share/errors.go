@@ -5,4 +5,6 @@ import "errors"
 var (
 	// ErrNotAvailable is returned whenever DA sampling fails.
 	ErrNotAvailable = errors.New("share: data not available")
+	// ErrInvalidSquare is returned when a data square breaks the layout rules.
+	ErrInvalidSquare = errors.New("share: invalid square layout")
 )
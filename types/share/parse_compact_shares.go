@@ -0,0 +1,155 @@
+package share
+
+import (
+	"fmt"
+
+	"github.com/celestiaorg/celestia-openrpc/types/appconsts"
+)
+
+// ParseCompactShares returns the units contained in the compact shares of a
+// single namespace, such as the shares of TxNamespace or PayForBlobNamespace
+// returned by Share.GetSharesByNamespace. Each unit is a raw transaction
+// without its length delimiter, i.e. a state.Tx.
+//
+// If the shares start in the middle of a sequence, parsing starts at the first
+// unit starting in them, located by their reserved bytes, and stops at the
+// last unit completed in them.
+func ParseCompactShares(shares []Share) (units [][]byte, err error) {
+	if len(shares) == 0 {
+		return nil, nil
+	}
+	parsed, err := parseCompactShares(shares)
+	if err != nil {
+		return nil, err
+	}
+	units = make([][]byte, len(parsed))
+	for i, u := range parsed {
+		units[i] = u.data
+	}
+	return units, nil
+}
+
+// rawUnit is a unit parsed from the raw data of compact shares.
+type rawUnit struct {
+	data []byte
+	// start and end are the range of the unit in the raw data, including its
+	// length delimiter.
+	start, end int
+}
+
+func parseCompactShares(shares []Share) ([]rawUnit, error) {
+	if err := validateCompactShares(shares); err != nil {
+		return nil, err
+	}
+
+	rawData, err := extractRawData(shares)
+	if err != nil {
+		return nil, err
+	}
+
+	isStart, err := shares[0].IsSequenceStart()
+	if err != nil {
+		return nil, err
+	}
+	if !isStart {
+		return parseRawData(rawData, false)
+	}
+	sequenceLen, err := shares[0].SequenceLen()
+	if err != nil {
+		return nil, err
+	}
+	if int(sequenceLen) > len(rawData) {
+		return nil, fmt.Errorf("compact shares hold %d of the %d bytes of their sequence", len(rawData), sequenceLen)
+	}
+	// the rest is padding of the last share
+	return parseRawData(rawData[:sequenceLen], true)
+}
+
+// validateCompactShares returns an error unless the shares are compact shares
+// of supported versions in a single namespace and a single sequence.
+func validateCompactShares(shares []Share) error {
+	first, err := shares[0].Namespace()
+	if err != nil {
+		return err
+	}
+	for i := range shares {
+		if err := shares[i].Validate(); err != nil {
+			return err
+		}
+		ns, err := shares[i].Namespace()
+		if err != nil {
+			return err
+		}
+		if !ns.Equals(first) {
+			return fmt.Errorf("share %d is of namespace %x, not %x", i, ns.Bytes(), first.Bytes())
+		}
+		isCompact, err := shares[i].IsCompactShare()
+		if err != nil {
+			return err
+		}
+		if !isCompact {
+			return fmt.Errorf("share %d is not a compact share", i)
+		}
+		if err := shares[i].DoesSupportVersions(appconsts.SupportedShareVersions); err != nil {
+			return err
+		}
+		isStart, err := shares[i].IsSequenceStart()
+		if err != nil {
+			return err
+		}
+		if i > 0 && isStart {
+			return fmt.Errorf("share %d starts a second sequence", i)
+		}
+	}
+	return nil
+}
+
+// extractRawData returns the raw data representing complete transactions
+// contained in the shares. The raw data does not contain the namespace, info
+// byte, sequence length, or reserved bytes. Starts reading raw data at the
+// first unit starting in the shares, located by their reserved bytes.
+func extractRawData(shares []Share) (rawData []byte, err error) {
+	for i := 0; i < len(shares); i++ {
+		var raw []byte
+		if len(rawData) == 0 {
+			// empty until a share with the start of a unit
+			raw, err = shares[i].RawDataUsingReserved()
+		} else {
+			raw, err = shares[i].RawData()
+		}
+		if err != nil {
+			return nil, err
+		}
+		rawData = append(rawData, raw...)
+	}
+	return rawData, nil
+}
+
+// parseRawData returns the units (transactions or PayForBlob transactions)
+// contained in raw data by parsing the unit length delimiter prefixed to each
+// unit. If complete is set, the raw data must end with a complete unit.
+func parseRawData(rawData []byte, complete bool) (units []rawUnit, err error) {
+	units = make([]rawUnit, 0)
+	offset := 0
+	for {
+		actualData, unitLen, err := ParseDelimiter(rawData)
+		if err != nil {
+			return nil, err
+		}
+		// the rest of raw data is padding
+		if unitLen == 0 {
+			return units, nil
+		}
+		// the rest of actual data contains only part of the next transaction
+		if unitLen > uint64(len(actualData)) {
+			if complete {
+				return nil, fmt.Errorf("unit %d of %d bytes is cut off after %d bytes", len(units), unitLen, len(actualData))
+			}
+			return units, nil
+		}
+		start := offset
+		offset += len(rawData) - len(actualData) + int(unitLen)
+		rawData = actualData[unitLen:]
+		units = append(units, rawUnit{data: actualData[:unitLen], start: start, end: offset})
+	}
+}
@@ -3,6 +3,7 @@ package share
 import (
 	"bytes"
 	"encoding/binary"
+	"encoding/json"
 	"fmt"
 
 	"github.com/celestiaorg/nmt"
@@ -67,6 +68,16 @@ func NewShare(data []byte) (*Share, error) {
 	return &Share{data}, nil
 }
 
+// MarshalJSON encodes the share as celestia-node does, as raw bytes.
+func (s Share) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.data)
+}
+
+// UnmarshalJSON decodes a share encoded as raw bytes.
+func (s *Share) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, &s.data)
+}
+
 func (s *Share) Validate() error {
 	return validateSize(s.data)
 }
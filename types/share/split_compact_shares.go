@@ -0,0 +1,187 @@
+package share
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/celestiaorg/celestia-openrpc/types/appconsts"
+	"github.com/celestiaorg/celestia-openrpc/types/namespace"
+)
+
+// CompactShareSplitter will write raw data compactly across a progressively
+// increasing set of shares. It is used to lazily split block data such as
+// transactions or PayForBlob transactions into shares.
+type CompactShareSplitter struct {
+	shares       []Share
+	shareBuilder *Builder
+	namespace    namespace.Namespace
+	done         bool
+	shareVersion uint8
+}
+
+// NewCompactShareSplitter returns a CompactShareSplitter using the provided
+// namespace and shareVersion.
+func NewCompactShareSplitter(ns namespace.Namespace, shareVersion uint8) *CompactShareSplitter {
+	sb, err := NewBuilder(ns, shareVersion, true).Init()
+	if err != nil {
+		panic(err)
+	}
+
+	return &CompactShareSplitter{
+		shares:       []Share{},
+		namespace:    ns,
+		shareVersion: shareVersion,
+		shareBuilder: sb,
+	}
+}
+
+// WriteTx adds the delimited data for the provided tx to the underlying compact
+// share splitter.
+func (css *CompactShareSplitter) WriteTx(tx []byte) error {
+	rawData, err := MarshalDelimitedTx(tx)
+	if err != nil {
+		return fmt.Errorf("included Tx in mem-pool that can not be encoded %v", tx)
+	}
+	return css.write(rawData)
+}
+
+// write adds the delimited data to the underlying compact shares.
+func (css *CompactShareSplitter) write(rawData []byte) error {
+	if css.done {
+		// remove the last element
+		if !css.shareBuilder.IsEmptyShare() {
+			css.shares = css.shares[:len(css.shares)-1]
+		}
+		css.done = false
+	}
+
+	if err := css.shareBuilder.MaybeWriteReservedBytes(); err != nil {
+		return err
+	}
+
+	for {
+		rawDataLeftOver := css.shareBuilder.AddData(rawData)
+		if rawDataLeftOver == nil {
+			break
+		}
+		if err := css.stackPending(); err != nil {
+			return err
+		}
+
+		rawData = rawDataLeftOver
+	}
+
+	if css.shareBuilder.AvailableBytes() == 0 {
+		if err := css.stackPending(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// stackPending will build & add the pending share to accumulated shares
+func (css *CompactShareSplitter) stackPending() error {
+	pendingShare, err := css.shareBuilder.Build()
+	if err != nil {
+		return err
+	}
+	css.shares = append(css.shares, *pendingShare)
+
+	// Now we need to create a new builder
+	css.shareBuilder, err = NewBuilder(css.namespace, css.shareVersion, false).Init()
+	return err
+}
+
+// Export returns the underlying compact shares
+func (css *CompactShareSplitter) Export() ([]Share, error) {
+	if css.isEmpty() {
+		return []Share{}, nil
+	}
+
+	// in case Export is called multiple times
+	if css.done {
+		return css.shares, nil
+	}
+
+	var bytesOfPadding int
+	// add the pending share to the current shares before returning
+	if !css.shareBuilder.IsEmptyShare() {
+		bytesOfPadding = css.shareBuilder.ZeroPadIfNecessary()
+		if err := css.stackPending(); err != nil {
+			return []Share{}, err
+		}
+	}
+
+	sequenceLen := css.sequenceLen(bytesOfPadding)
+	if err := css.writeSequenceLen(sequenceLen); err != nil {
+		return []Share{}, err
+	}
+	css.done = true
+	return css.shares, nil
+}
+
+// writeSequenceLen writes the sequence length to the first share.
+func (css *CompactShareSplitter) writeSequenceLen(sequenceLen uint32) error {
+	if css.isEmpty() {
+		return nil
+	}
+
+	// We may find a more efficient way to write seqLen
+	b := NewBuilder(css.namespace, css.shareVersion, true)
+	b.ImportRawShare(css.shares[0].ToBytes())
+	if err := b.WriteSequenceLen(sequenceLen); err != nil {
+		return err
+	}
+
+	firstShare, err := b.Build()
+	if err != nil {
+		return err
+	}
+
+	// replace existing first share with new first share
+	css.shares[0] = *firstShare
+
+	return nil
+}
+
+// sequenceLen returns the total length in bytes of all units (transactions or
+// PayForBlob transactions) written to this splitter. sequenceLen does not
+// include the number of bytes occupied by the namespace ID, the share info
+// byte, or the reserved bytes. sequenceLen does include the unit length
+// delimiter prefixed to each unit.
+func (css *CompactShareSplitter) sequenceLen(bytesOfPadding int) uint32 {
+	if len(css.shares) == 0 {
+		return 0
+	}
+	if len(css.shares) == 1 {
+		return uint32(appconsts.FirstCompactShareContentSize) - uint32(bytesOfPadding)
+	}
+
+	continuationSharesCount := len(css.shares) - 1
+	continuationSharesSequenceLen := continuationSharesCount * appconsts.ContinuationCompactShareContentSize
+	return uint32(appconsts.FirstCompactShareContentSize + continuationSharesSequenceLen - bytesOfPadding)
+}
+
+// isEmpty returns whether this compact share splitter is empty.
+func (css *CompactShareSplitter) isEmpty() bool {
+	return len(css.shares) == 0 && css.shareBuilder.IsEmptyShare()
+}
+
+// Count returns the number of shares that would be made if `Export` was invoked
+// on this compact share splitter.
+func (css *CompactShareSplitter) Count() int {
+	if !css.shareBuilder.IsEmptyShare() && !css.done {
+		// pending share is non-empty, so it will be zero padded and added to shares during export
+		return len(css.shares) + 1
+	}
+	return len(css.shares)
+}
+
+// MarshalDelimitedTx prefixes a transaction with the length of the transaction
+// encoded as a varint.
+func MarshalDelimitedTx(tx []byte) ([]byte, error) {
+	lenBuf := make([]byte, binary.MaxVarintLen64)
+	length := uint64(len(tx))
+	n := binary.PutUvarint(lenBuf, length)
+	return append(lenBuf[:n], tx...), nil
+}
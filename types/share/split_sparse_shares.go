@@ -104,3 +104,13 @@ func (sss *SparseShareSplitter) Export() []Share {
 func (sss *SparseShareSplitter) Count() int {
 	return len(sss.shares)
 }
+
+// SparseSharesNeeded returns the number of shares needed to hold a sequence
+// of sparse shares of the given length.
+func SparseSharesNeeded(sequenceLen uint32) int {
+	if sequenceLen <= appconsts.FirstSparseShareContentSize {
+		return 1
+	}
+	rest := int(sequenceLen) - appconsts.FirstSparseShareContentSize
+	return 1 + (rest+appconsts.ContinuationSparseShareContentSize-1)/appconsts.ContinuationSparseShareContentSize
+}
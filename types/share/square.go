@@ -0,0 +1,334 @@
+package share
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/celestiaorg/rsmt2d"
+	"golang.org/x/exp/slices"
+
+	"github.com/celestiaorg/celestia-openrpc/types/appconsts"
+	"github.com/celestiaorg/celestia-openrpc/types/namespace"
+)
+
+// ElementKind is the kind of content of a data square.
+type ElementKind int
+
+const (
+	// TxElement is a transaction in the compact shares of TxNamespace.
+	TxElement ElementKind = iota
+	// PFBElement is a PayForBlob transaction, wrapped with the indexes of its
+	// blobs, in the compact shares of PayForBlobNamespace.
+	PFBElement
+	// BlobElement is a blob.
+	BlobElement
+	// ReservedPaddingElement is the padding between the compact shares and
+	// the first blob.
+	ReservedPaddingElement
+	// NamespacePaddingElement is the padding after a blob, so that the next
+	// blob starts at an index conforming to the blob share commitment rules.
+	NamespacePaddingElement
+	// TailPaddingElement is the padding after the last blob, up to the end of
+	// the square.
+	TailPaddingElement
+)
+
+func (k ElementKind) String() string {
+	switch k {
+	case TxElement:
+		return "tx"
+	case PFBElement:
+		return "pfb"
+	case BlobElement:
+		return "blob"
+	case ReservedPaddingElement:
+		return "reserved padding"
+	case NamespacePaddingElement:
+		return "namespace padding"
+	case TailPaddingElement:
+		return "tail padding"
+	default:
+		return fmt.Sprintf("ElementKind(%d)", int(k))
+	}
+}
+
+// Range is a range of shares in the original data square, in row-major order.
+type Range struct {
+	// Start is the index of the first share.
+	Start int
+	// End is the index after the last share.
+	End int
+}
+
+// SquareElement is a piece of content of a data square.
+type SquareElement struct {
+	Kind      ElementKind
+	Namespace Namespace
+	// Shares is the range of the shares taken up by the element. Txs and PFBs
+	// may share their first and last shares with others, and consecutive
+	// padding shares of the same kind make up a single element.
+	Shares Range
+	// Data is the raw tx or PFB, or the data of a blob.
+	Data []byte
+	// ShareVersion is the share version of a blob.
+	ShareVersion uint8
+	// Index is the index of the first share of a blob in the extended data
+	// square, as the node reports it.
+	Index int
+}
+
+// Square is the content of a data square.
+type Square struct {
+	// Width is the width of the original data square.
+	Width int
+	// Elements are ordered by their first share.
+	Elements []SquareElement
+}
+
+// Txs returns the transactions of the square.
+func (s *Square) Txs() []SquareElement {
+	return s.filter(TxElement)
+}
+
+// PFBs returns the PayForBlob transactions of the square.
+func (s *Square) PFBs() []SquareElement {
+	return s.filter(PFBElement)
+}
+
+// Blobs returns the blobs of the square.
+func (s *Square) Blobs() []SquareElement {
+	return s.filter(BlobElement)
+}
+
+func (s *Square) filter(kind ElementKind) []SquareElement {
+	var elements []SquareElement
+	for _, e := range s.Elements {
+		if e.Kind == kind {
+			elements = append(elements, e)
+		}
+	}
+	return elements
+}
+
+// ParseSquare walks the original data square of eds and returns its content.
+// It returns an error wrapping ErrInvalidSquare if the square breaks the
+// layout rules: transactions first, then PayForBlob transactions, reserved
+// padding, blobs ordered by namespace at indexes following the blob share
+// commitment rules with namespace padding between them, and tail padding.
+func ParseSquare(eds *rsmt2d.ExtendedDataSquare) (*Square, error) {
+	if eds == nil {
+		return nil, errors.New("share: nil extended data square")
+	}
+	shares, err := FromBytes(eds.FlattenedODS())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSquare, err)
+	}
+	p := &squareParser{
+		square: &Square{Width: int(eds.Width() / 2)},
+		shares: shares,
+	}
+	if err := p.parse(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSquare, err)
+	}
+	return p.square, nil
+}
+
+type squareParser struct {
+	square *Square
+	shares []Share
+	// cursor is the index of the next share to parse.
+	cursor int
+	// lastBlob is the last blob parsed, if any.
+	lastBlob *SquareElement
+}
+
+func (p *squareParser) parse() error {
+	if err := p.parseCompact(namespace.TxNamespace, TxElement); err != nil {
+		return err
+	}
+	if err := p.parseCompact(namespace.PayForBlobNamespace, PFBElement); err != nil {
+		return err
+	}
+	p.parsePadding(ReservedPaddingElement)
+
+	for p.cursor < len(p.shares) {
+		sh := &p.shares[p.cursor]
+		ns, err := sh.Namespace()
+		if err != nil {
+			return err
+		}
+		switch {
+		case ns.IsTailPadding():
+			p.parsePadding(TailPaddingElement)
+			if p.cursor < len(p.shares) {
+				return fmt.Errorf("share %d follows the tail padding", p.cursor)
+			}
+			return nil
+		case ns.IsReserved():
+			return fmt.Errorf("share %d of reserved namespace %x is among the blobs", p.cursor, ns.Bytes())
+		}
+		padding, err := sh.isNamespacePadding()
+		if err != nil {
+			return err
+		}
+		if padding {
+			if p.lastBlob == nil || !p.lastBlob.Namespace.Equals(ns.Bytes()) {
+				return fmt.Errorf("namespace padding at share %d does not follow a blob of its namespace", p.cursor)
+			}
+			p.parsePadding(NamespacePaddingElement)
+			continue
+		}
+		if err := p.parseBlob(ns); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseCompact parses the compact shares of ns at the cursor, if any.
+func (p *squareParser) parseCompact(ns namespace.Namespace, kind ElementKind) error {
+	start := p.cursor
+	for p.cursor < len(p.shares) && p.namespaceAt(p.cursor).Equals(ns) {
+		p.cursor++
+	}
+	if start == p.cursor {
+		return nil
+	}
+	shares := p.shares[start:p.cursor]
+	isStart, err := shares[0].IsSequenceStart()
+	if err != nil {
+		return err
+	}
+	if !isStart {
+		return fmt.Errorf("compact share %d does not start a sequence", start)
+	}
+	units, err := parseCompactShares(shares)
+	if err != nil {
+		return fmt.Errorf("compact shares at %d: %w", start, err)
+	}
+	// the share holding a byte of the raw data
+	shareOf := func(offset int) int {
+		if offset < appconsts.FirstCompactShareContentSize {
+			return start
+		}
+		return start + 1 + (offset-appconsts.FirstCompactShareContentSize)/appconsts.ContinuationCompactShareContentSize
+	}
+	for _, u := range units {
+		p.square.Elements = append(p.square.Elements, SquareElement{
+			Kind:      kind,
+			Namespace: ns.Bytes(),
+			Shares:    Range{Start: shareOf(u.start), End: shareOf(u.end-1) + 1},
+			Data:      u.data,
+			Index:     -1,
+		})
+	}
+	return nil
+}
+
+// parsePadding parses the padding shares of kind at the cursor, if any.
+func (p *squareParser) parsePadding(kind ElementKind) {
+	start := p.cursor
+	for p.cursor < len(p.shares) && p.isPadding(p.cursor, kind) {
+		p.cursor++
+	}
+	if start == p.cursor {
+		return
+	}
+	p.square.Elements = append(p.square.Elements, SquareElement{
+		Kind:      kind,
+		Namespace: p.namespaceAt(start).Bytes(),
+		Shares:    Range{Start: start, End: p.cursor},
+		Index:     -1,
+	})
+}
+
+func (p *squareParser) isPadding(i int, kind ElementKind) bool {
+	ns := p.namespaceAt(i)
+	switch kind {
+	case ReservedPaddingElement:
+		return ns.IsReservedPadding()
+	case TailPaddingElement:
+		return ns.IsTailPadding()
+	case NamespacePaddingElement:
+		padding, err := p.shares[i].isNamespacePadding()
+		return err == nil && padding && ns.Equals(p.namespaceAt(p.cursor-1))
+	default:
+		return false
+	}
+}
+
+// parseBlob parses the blob of namespace ns starting at the cursor.
+func (p *squareParser) parseBlob(ns namespace.Namespace) error {
+	start := p.cursor
+	first := &p.shares[start]
+	infoByte, err := first.InfoByte()
+	if err != nil {
+		return err
+	}
+	if !infoByte.IsSequenceStart() {
+		return fmt.Errorf("blob share %d does not start a sequence", start)
+	}
+	if !slices.Contains(appconsts.SupportedShareVersions, infoByte.Version()) {
+		return fmt.Errorf("blob at share %d has unsupported share version %d", start, infoByte.Version())
+	}
+	if p.lastBlob != nil && p.lastBlob.Namespace.IsGreater(ns.Bytes()) {
+		return fmt.Errorf("blob at share %d is not ordered by namespace", start)
+	}
+	sequenceLen, err := first.SequenceLen()
+	if err != nil {
+		return err
+	}
+
+	count := SparseSharesNeeded(sequenceLen)
+	if start+count > len(p.shares) {
+		return fmt.Errorf("blob at share %d takes %d shares, beyond the end of the square", start, count)
+	}
+	// the first blob follows the compact shares at an index only bounded
+	// by the commitment rules, the others follow the previous blob
+	cursor := start
+	if p.lastBlob != nil {
+		cursor = p.lastBlob.Shares.End
+	}
+	if want := NextShareIndex(cursor, count, appconsts.DefaultSubtreeRootThreshold); start != want {
+		return fmt.Errorf("blob at share %d should start at share %d", start, want)
+	}
+
+	var data []byte
+	for i := start; i < start+count; i++ {
+		if i > start {
+			isStart, err := p.shares[i].IsSequenceStart()
+			if err != nil {
+				return err
+			}
+			if isStart || !p.namespaceAt(i).Equals(ns) {
+				return fmt.Errorf("blob at share %d ends at share %d, before its %d bytes", start, i, sequenceLen)
+			}
+		}
+		raw, err := p.shares[i].RawData()
+		if err != nil {
+			return err
+		}
+		data = append(data, raw...)
+	}
+	p.cursor = start + count
+
+	width := p.square.Width
+	blob := SquareElement{
+		Kind:         BlobElement,
+		Namespace:    ns.Bytes(),
+		Shares:       Range{Start: start, End: p.cursor},
+		Data:         data[:sequenceLen],
+		ShareVersion: infoByte.Version(),
+		Index:        start/width*2*width + start%width,
+	}
+	p.square.Elements = append(p.square.Elements, blob)
+	p.lastBlob = &blob
+	return nil
+}
+
+// namespaceAt returns the namespace of the share at index i, whose size was
+// validated.
+func (p *squareParser) namespaceAt(i int) namespace.Namespace {
+	ns, _ := p.shares[i].Namespace()
+	return ns
+}
@@ -0,0 +1,143 @@
+package share_test
+
+import (
+	"bytes"
+	"testing"
+
+	gsblob "github.com/celestiaorg/go-square/blob"
+	gsnamespace "github.com/celestiaorg/go-square/namespace"
+	gsshares "github.com/celestiaorg/go-square/shares"
+	"github.com/celestiaorg/go-square/square"
+	"github.com/celestiaorg/rsmt2d"
+	"github.com/stretchr/testify/require"
+
+	"github.com/celestiaorg/celestia-openrpc/types/appconsts"
+	"github.com/celestiaorg/celestia-openrpc/types/share"
+)
+
+// testSquare returns the shares of a square of txs, PFBs and blobs, and the
+// builder that laid them out.
+func testSquare(t *testing.T) ([][]byte, *square.Builder, [][]byte, []*gsblob.Blob) {
+	t.Helper()
+	builder, err := square.NewBuilder(appconsts.DefaultSquareSizeUpperBound, appconsts.DefaultSubtreeRootThreshold)
+	require.NoError(t, err)
+
+	txs := [][]byte{[]byte("tx"), bytes.Repeat([]byte{1}, 1_000)}
+	for _, tx := range txs {
+		require.True(t, builder.AppendTx(tx))
+	}
+	var blobs []*gsblob.Blob
+	for _, b := range []struct {
+		id   byte
+		size int
+	}{
+		{2, 10},
+		// take subtrees of 8 shares, so they are preceded by padding
+		{2, 150_000},
+		{1, 150_000},
+	} {
+		ns := gsnamespace.MustNewV0(bytes.Repeat([]byte{b.id}, gsnamespace.NamespaceVersionZeroIDSize))
+		blob := gsblob.New(ns, bytes.Repeat([]byte{b.id}, b.size), appconsts.ShareVersionZero)
+		require.True(t, builder.AppendBlobTx(&gsblob.BlobTx{Tx: []byte("pfb"), Blobs: []*gsblob.Blob{blob}}))
+		blobs = append(blobs, blob)
+	}
+	sq, err := builder.Export()
+	require.NoError(t, err)
+	return gsshares.ToBytes(sq), builder, txs, blobs
+}
+
+func extend(t *testing.T, shares [][]byte) *rsmt2d.ExtendedDataSquare {
+	t.Helper()
+	eds, err := rsmt2d.ComputeExtendedDataSquare(shares, share.DefaultRSMT2DCodec(), rsmt2d.NewDefaultTree)
+	require.NoError(t, err)
+	return eds
+}
+
+func TestParseSquare(t *testing.T) {
+	shares, builder, txs, blobs := testSquare(t)
+	sq, err := share.ParseSquare(extend(t, shares))
+	require.NoError(t, err)
+	require.Equal(t, 32, sq.Width)
+
+	parsedTxs := sq.Txs()
+	require.Len(t, parsedTxs, len(txs))
+	for i, tx := range parsedTxs {
+		require.Equal(t, txs[i], tx.Data)
+	}
+	require.Equal(t, share.Range{Start: 0, End: 1}, parsedTxs[0].Shares)
+	require.Equal(t, share.Range{Start: 0, End: 3}, parsedTxs[1].Shares)
+	require.Len(t, sq.PFBs(), len(blobs))
+
+	parsedBlobs := sq.Blobs()
+	require.Len(t, parsedBlobs, len(blobs))
+	// ordered by namespace, and by PFB within a namespace
+	for i, want := range []int{2, 0, 1} {
+		b := parsedBlobs[i]
+		require.Equal(t, blobs[want].Namespace().Bytes(), []byte(b.Namespace))
+		require.Equal(t, blobs[want].Data, b.Data)
+		start, err := builder.FindBlobStartingIndex(len(txs)+want, 0)
+		require.NoError(t, err)
+		require.Equal(t, start, b.Shares.Start)
+		length, err := builder.BlobShareLength(len(txs)+want, 0)
+		require.NoError(t, err)
+		require.Equal(t, start+length, b.Shares.End)
+		require.Equal(t, start/sq.Width*2*sq.Width+start%sq.Width, b.Index)
+	}
+
+	var kinds []share.ElementKind
+	end := 0
+	for _, e := range sq.Elements {
+		require.LessOrEqual(t, e.Shares.Start, end, e.Kind)
+		end = e.Shares.End
+		if len(kinds) == 0 || kinds[len(kinds)-1] != e.Kind {
+			kinds = append(kinds, e.Kind)
+		}
+	}
+	require.Equal(t, sq.Width*sq.Width, end)
+	require.Equal(t, []share.ElementKind{
+		share.TxElement,
+		share.PFBElement,
+		share.ReservedPaddingElement,
+		share.BlobElement,
+		share.NamespacePaddingElement,
+		share.BlobElement,
+		share.TailPaddingElement,
+	}, kinds)
+}
+
+func TestParseSquare_Invalid(t *testing.T) {
+	shares, _, _, _ := testSquare(t)
+	sq, err := share.ParseSquare(extend(t, shares))
+	require.NoError(t, err)
+	blobs := sq.Blobs()
+	tail := shares[len(shares)-1]
+
+	tests := []struct {
+		name   string
+		mutate func(shares [][]byte)
+		err    string
+	}{
+		{"tail padding first", func(s [][]byte) { s[0] = tail }, "follows the tail padding"},
+		{"data after tail padding", func(s [][]byte) { s[len(s)-1] = s[blobs[0].Shares.Start] }, "follows the tail padding"},
+		{"blob cut short", func(s [][]byte) { s[blobs[2].Shares.Start+1] = s[blobs[2].Shares.Start] }, "before its"},
+		{"blobs out of order", func(s [][]byte) {
+			s[blobs[0].Shares.Start], s[blobs[1].Shares.Start] = s[blobs[1].Shares.Start], s[blobs[0].Shares.Start]
+		}, ""},
+		{"misaligned blob", func(s [][]byte) {
+			start := blobs[2].Shares.Start
+			copy(s[start-1:], s[start:blobs[2].Shares.End])
+			s[blobs[2].Shares.End-1] = s[blobs[2].Shares.End]
+		}, "should start at share"},
+		{"tx among blobs", func(s [][]byte) { s[blobs[0].Shares.End] = s[0] }, "reserved namespace"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mutated := make([][]byte, len(shares))
+			copy(mutated, shares)
+			tt.mutate(mutated)
+			_, err := share.ParseSquare(extend(t, mutated))
+			require.ErrorIs(t, err, share.ErrInvalidSquare)
+			require.ErrorContains(t, err, tt.err)
+		})
+	}
+}
@@ -1,6 +1,9 @@
 package share
 
-import "bytes"
+import (
+	"bytes"
+	"encoding/binary"
+)
 
 // zeroPadIfNecessary pads the share with trailing zero bytes if the provided
 // share has fewer bytes than width. Returns the share unmodified if the
@@ -17,3 +20,36 @@ func zeroPadIfNecessary(share []byte, width int) (padded []byte, bytesOfPadding
 	share = append(share, padding...)
 	return share, missingBytes
 }
+
+// ParseDelimiter attempts to parse a varint length delimiter from the input
+// provided. It returns the input without the len delimiter bytes, the length
+// parsed from the varint optionally an error. Unit length delimiters are used
+// in compact shares where units (i.e. a transaction) are prefixed with a length
+// delimiter that is encoded as a varint. Input should not contain the namespace
+// ID or info byte of a share.
+func ParseDelimiter(input []byte) (inputWithoutLenDelimiter []byte, unitLen uint64, err error) {
+	if len(input) == 0 {
+		return input, 0, nil
+	}
+
+	l := binary.MaxVarintLen64
+	if len(input) < binary.MaxVarintLen64 {
+		l = len(input)
+	}
+
+	delimiter, _ := zeroPadIfNecessary(input[:l:l], binary.MaxVarintLen64)
+
+	// read the length of the data
+	r := bytes.NewBuffer(delimiter)
+	dataLen, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// calculate the number of bytes used by the delimiter
+	lenBuf := make([]byte, binary.MaxVarintLen64)
+	n := binary.PutUvarint(lenBuf, dataLen)
+
+	// return the input without the length delimiter
+	return input[n:], dataLen, nil
+}
@@ -0,0 +1,267 @@
+package square
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/celestiaorg/celestia-openrpc/types/appconsts"
+	"github.com/celestiaorg/celestia-openrpc/types/blob"
+	"github.com/celestiaorg/celestia-openrpc/types/namespace"
+	"github.com/celestiaorg/celestia-openrpc/types/share"
+
+	gsblob "github.com/celestiaorg/go-square/blob"
+)
+
+// worstCaseShareIndex is the share index assumed for each blob of a PFB when
+// counting the shares of the PFBs, before the blobs are laid out. It is the
+// last share of the largest square of celestia-app v1, as protobuf encodes
+// larger indexes with more bytes.
+const worstCaseShareIndex = 128 * 128
+
+// Builder lays out transactions and blobs in a data square as celestia-app
+// does: transactions first, then PayForBlob transactions wrapped with the
+// indexes of their blobs, then the blobs ordered by namespace, at indexes
+// following the blob share commitment rules.
+type Builder struct {
+	maxSquareSize        int
+	subtreeRootThreshold int
+	// currentSize is an overestimate of the number of shares taken up by
+	// the content appended so far, assuming the worst padding for blobs.
+	currentSize int
+
+	txs   [][]byte
+	pfbs  []*pfb
+	blobs []*element
+
+	txCounter  *share.CompactShareCounter
+	pfbCounter *share.CompactShareCounter
+}
+
+// pfb is a PayForBlob transaction with the share indexes of its blobs.
+type pfb struct {
+	tx           []byte
+	shareIndexes []uint32
+}
+
+// element is a blob to lay out.
+type element struct {
+	blob      *blob.Blob
+	pfbIndex  int
+	blobIndex int
+	numShares int
+	// maxPadding is the largest number of padding shares that may precede
+	// the blob for it to start at an index following the commitment rules.
+	maxPadding int
+}
+
+// NewBuilder returns a Builder of squares of at most maxSquareSize shares
+// wide, such as appconsts.DefaultSquareSizeUpperBound, laying out blobs with
+// subtreeRootThreshold, such as appconsts.DefaultSubtreeRootThreshold.
+func NewBuilder(maxSquareSize, subtreeRootThreshold int) (*Builder, error) {
+	if maxSquareSize <= 0 {
+		return nil, errors.New("square: max square size must be strictly positive")
+	}
+	if share.RoundUpPowerOfTwo(maxSquareSize) != maxSquareSize {
+		return nil, errors.New("square: max square size must be a power of two")
+	}
+	if subtreeRootThreshold <= 0 {
+		return nil, errors.New("square: subtree root threshold must be strictly positive")
+	}
+	return &Builder{
+		maxSquareSize:        maxSquareSize,
+		subtreeRootThreshold: subtreeRootThreshold,
+		txCounter:            share.NewCompactShareCounter(),
+		pfbCounter:           share.NewCompactShareCounter(),
+	}, nil
+}
+
+// AppendTx appends a transaction to the square. It reports whether the
+// transaction fits in the square.
+func (b *Builder) AppendTx(tx []byte) bool {
+	diff := b.txCounter.Add(len(tx))
+	if !b.canFit(diff) {
+		b.txCounter.Revert()
+		return false
+	}
+	b.txs = append(b.txs, tx)
+	b.currentSize += diff
+	return true
+}
+
+// AppendBlobTx appends the PayForBlobs transaction tx and the blobs it pays
+// for to the square. It reports whether they fit in the square.
+//
+// The layout of the blobs depends on the size of tx, so predicting the data
+// root of a block needs the exact PayForBlobs transaction of the block.
+func (b *Builder) AppendBlobTx(tx []byte, blobs ...*blob.Blob) bool {
+	if len(blobs) == 0 {
+		return false
+	}
+	worstCase := make([]uint32, len(blobs))
+	for i := range worstCase {
+		worstCase[i] = worstCaseShareIndex
+	}
+	wrapped, err := gsblob.MarshalIndexWrapper(tx, worstCase...)
+	if err != nil {
+		return false
+	}
+	diff := b.pfbCounter.Add(len(wrapped))
+
+	elements := make([]*element, len(blobs))
+	for i, bl := range blobs {
+		numShares := share.SparseSharesNeeded(uint32(len(bl.Data)))
+		elements[i] = &element{
+			blob:      bl,
+			pfbIndex:  len(b.pfbs),
+			blobIndex: i,
+			numShares: numShares,
+			// a blob whose first subtree is n shares wide is preceded by at
+			// most n-1 padding shares
+			maxPadding: share.SubTreeWidth(numShares, b.subtreeRootThreshold) - 1,
+		}
+		diff += numShares + elements[i].maxPadding
+	}
+	if !b.canFit(diff) {
+		b.pfbCounter.Revert()
+		return false
+	}
+	b.blobs = append(b.blobs, elements...)
+	b.pfbs = append(b.pfbs, &pfb{tx: tx, shareIndexes: make([]uint32, len(blobs))})
+	b.currentSize += diff
+	return true
+}
+
+func (b *Builder) canFit(shares int) bool {
+	return b.currentSize+shares <= b.maxSquareSize*b.maxSquareSize
+}
+
+func (b *Builder) isEmpty() bool {
+	return b.txCounter.Size() == 0 && b.pfbCounter.Size() == 0
+}
+
+// Export lays out the content appended so far in the smallest square
+// holding it, extends the square and computes its data availability header.
+func (b *Builder) Export() (*Square, error) {
+	if b.isEmpty() {
+		shares, err := share.NamespacePaddingShares(namespace.TailPaddingNamespace, appconsts.MinShareCount)
+		if err != nil {
+			return nil, err
+		}
+		return newSquare(appconsts.MinSquareSize, shares, nil)
+	}
+	width := share.BlobMinSquareSize(b.currentSize)
+
+	// blobs of the same namespace keep the order of their PFBs
+	blobs := make([]*element, len(b.blobs))
+	copy(blobs, b.blobs)
+	sort.SliceStable(blobs, func(i, j int) bool {
+		return bytes.Compare(blobs[i].blob.Namespace().Bytes(), blobs[j].blob.Namespace().Bytes()) < 0
+	})
+
+	txWriter := share.NewCompactShareSplitter(namespace.TxNamespace, appconsts.ShareVersionZero)
+	for _, tx := range b.txs {
+		if err := txWriter.WriteTx(tx); err != nil {
+			return nil, fmt.Errorf("square: writing tx into compact shares: %w", err)
+		}
+	}
+
+	// the blobs start after the shares reserved for the txs and PFBs
+	nonReservedStart := b.txCounter.Size() + b.pfbCounter.Size()
+	cursor := nonReservedStart
+	endOfLastBlob := nonReservedStart
+	ranges := make([][]share.Range, len(b.pfbs))
+	for i, p := range b.pfbs {
+		ranges[i] = make([]share.Range, len(p.shareIndexes))
+	}
+	blobWriter := share.NewSparseShareSplitter()
+	for i, e := range blobs {
+		cursor = share.NextShareIndex(cursor, e.numShares, b.subtreeRootThreshold)
+		if i == 0 {
+			nonReservedStart = cursor
+		}
+		padding := cursor - endOfLastBlob
+		if padding > e.maxPadding {
+			return nil, fmt.Errorf("square: blob has %d padding shares, but %d was the max possible", padding, e.maxPadding)
+		}
+		if i > 0 {
+			if err := blobWriter.WriteNamespacePaddingShares(padding); err != nil {
+				return nil, fmt.Errorf("square: writing padding into sparse shares: %w", err)
+			}
+		}
+		if err := blobWriter.Write(e.blob.ShareVersion, e.blob.Namespace().Bytes(), e.blob.Data); err != nil {
+			return nil, fmt.Errorf("square: writing blob into sparse shares: %w", err)
+		}
+		b.pfbs[e.pfbIndex].shareIndexes[e.blobIndex] = uint32(cursor)
+		ranges[e.pfbIndex][e.blobIndex] = share.Range{Start: cursor, End: cursor + e.numShares}
+		cursor += e.numShares
+		endOfLastBlob = cursor
+	}
+
+	pfbWriter := share.NewCompactShareSplitter(namespace.PayForBlobNamespace, appconsts.ShareVersionZero)
+	for _, p := range b.pfbs {
+		wrapped, err := gsblob.MarshalIndexWrapper(p.tx, p.shareIndexes...)
+		if err != nil {
+			return nil, fmt.Errorf("square: marshaling pay for blob tx: %w", err)
+		}
+		if err := pfbWriter.WriteTx(wrapped); err != nil {
+			return nil, fmt.Errorf("square: writing pay for blob tx into compact shares: %w", err)
+		}
+	}
+
+	shares, err := writeSquare(txWriter, pfbWriter, blobWriter, nonReservedStart, width)
+	if err != nil {
+		return nil, err
+	}
+	return newSquare(width, shares, ranges)
+}
+
+// writeSquare writes the shares of the txs, the PFBs, the reserved padding up
+// to nonReservedStart, the blobs and the tail padding.
+func writeSquare(
+	txWriter, pfbWriter *share.CompactShareSplitter,
+	blobWriter *share.SparseShareSplitter,
+	nonReservedStart, width int,
+) ([]share.Share, error) {
+	totalShares := width * width
+	pfbStart := txWriter.Count()
+	paddingStart := pfbStart + pfbWriter.Count()
+	if nonReservedStart < paddingStart {
+		return nil, fmt.Errorf("square: blobs start at share %d, before the end of the txs and PFBs at %d",
+			nonReservedStart, paddingStart)
+	}
+	endOfLastBlob := nonReservedStart + blobWriter.Count()
+	if totalShares < endOfLastBlob {
+		return nil, fmt.Errorf("square: square of %d shares is too small to fit all blobs", totalShares)
+	}
+
+	txShares, err := txWriter.Export()
+	if err != nil {
+		return nil, fmt.Errorf("square: exporting tx shares: %w", err)
+	}
+	pfbShares, err := pfbWriter.Export()
+	if err != nil {
+		return nil, fmt.Errorf("square: exporting pay for blob shares: %w", err)
+	}
+
+	shares := make([]share.Share, totalShares)
+	copy(shares, txShares)
+	copy(shares[pfbStart:], pfbShares)
+	if blobWriter.Count() > 0 {
+		padding, err := share.NamespacePaddingShares(namespace.ReservedPaddingNamespace, nonReservedStart-paddingStart)
+		if err != nil {
+			return nil, err
+		}
+		copy(shares[paddingStart:], padding)
+		copy(shares[nonReservedStart:], blobWriter.Export())
+	}
+	if totalShares > endOfLastBlob {
+		padding, err := share.NamespacePaddingShares(namespace.TailPaddingNamespace, totalShares-endOfLastBlob)
+		if err != nil {
+			return nil, err
+		}
+		copy(shares[endOfLastBlob:], padding)
+	}
+	return shares, nil
+}
@@ -0,0 +1,119 @@
+package square_test
+
+import (
+	"bytes"
+	"testing"
+
+	gsblob "github.com/celestiaorg/go-square/blob"
+	gsshares "github.com/celestiaorg/go-square/shares"
+	gssquare "github.com/celestiaorg/go-square/square"
+	"github.com/celestiaorg/rsmt2d"
+	"github.com/stretchr/testify/require"
+
+	"github.com/celestiaorg/celestia-openrpc/types/appconsts"
+	"github.com/celestiaorg/celestia-openrpc/types/blob"
+	"github.com/celestiaorg/celestia-openrpc/types/core"
+	"github.com/celestiaorg/celestia-openrpc/types/share"
+	"github.com/celestiaorg/celestia-openrpc/types/square"
+)
+
+func TestBuilder(t *testing.T) {
+	builder, err := square.NewBuilder(appconsts.DefaultSquareSizeUpperBound, appconsts.DefaultSubtreeRootThreshold)
+	require.NoError(t, err)
+	reference, err := gssquare.NewBuilder(appconsts.DefaultSquareSizeUpperBound, appconsts.DefaultSubtreeRootThreshold)
+	require.NoError(t, err)
+
+	txs := [][]byte{[]byte("tx"), bytes.Repeat([]byte{1}, 1_000)}
+	for _, tx := range txs {
+		require.True(t, builder.AppendTx(tx))
+		require.True(t, reference.AppendTx(tx))
+	}
+	var blobs []*blob.Blob
+	for _, b := range []struct {
+		id   byte
+		size int
+	}{
+		{2, 10},
+		{2, 150_000},
+		{1, 150_000},
+		{3, 1_000},
+	} {
+		ns, err := share.NewBlobNamespaceV0(bytes.Repeat([]byte{b.id}, 10))
+		require.NoError(t, err)
+		bl, err := blob.NewBlobV0(ns, bytes.Repeat([]byte{b.id}, b.size))
+		require.NoError(t, err)
+		pfb := []byte("pfb")
+		require.True(t, builder.AppendBlobTx(pfb, bl))
+		require.True(t, reference.AppendBlobTx(&gsblob.BlobTx{Tx: pfb, Blobs: []*gsblob.Blob{&bl.Blob}}))
+		blobs = append(blobs, bl)
+	}
+
+	sq, err := builder.Export()
+	require.NoError(t, err)
+	want, err := reference.Export()
+	require.NoError(t, err)
+	require.Equal(t, want.Size(), sq.Width)
+	require.Equal(t, gsshares.ToBytes(want), share.ToBytes(sq.Shares))
+
+	for i := range blobs {
+		r, err := sq.BlobRange(i, 0)
+		require.NoError(t, err)
+		start, err := reference.FindBlobStartingIndex(len(txs)+i, 0)
+		require.NoError(t, err)
+		length, err := reference.BlobShareLength(len(txs)+i, 0)
+		require.NoError(t, err)
+		require.Equal(t, share.Range{Start: start, End: start + length}, r)
+	}
+	_, err = sq.BlobRange(0, 1)
+	require.Error(t, err)
+
+	// the square extends to the same data root as one built independently
+	eds, err := rsmt2d.ComputeExtendedDataSquare(
+		gsshares.ToBytes(want),
+		share.DefaultRSMT2DCodec(),
+		square.NewConstructor(uint(want.Size())),
+	)
+	require.NoError(t, err)
+	dah, err := core.NewDataAvailabilityHeader(eds)
+	require.NoError(t, err)
+	require.Equal(t, dah.Hash(), sq.DataHash)
+	require.True(t, dah.Equals(sq.DAH))
+
+	parsed, err := share.ParseSquare(sq.EDS)
+	require.NoError(t, err)
+	for i, b := range parsed.Blobs() {
+		require.Equal(t, blobs[[]int{2, 0, 1, 3}[i]].Data, b.Data)
+	}
+	index, err := sq.BlobIndex(0, 0)
+	require.NoError(t, err)
+	require.Equal(t, parsed.Blobs()[1].Index, index)
+}
+
+func TestBuilder_Empty(t *testing.T) {
+	builder, err := square.NewBuilder(appconsts.DefaultSquareSizeUpperBound, appconsts.DefaultSubtreeRootThreshold)
+	require.NoError(t, err)
+	sq, err := builder.Export()
+	require.NoError(t, err)
+	require.Equal(t, 1, sq.Width)
+	require.Equal(t, gsshares.ToBytes(gssquare.EmptySquare()), share.ToBytes(sq.Shares))
+	require.Len(t, sq.DAH.RowRoots, 2)
+}
+
+func TestBuilder_Full(t *testing.T) {
+	_, err := square.NewBuilder(3, appconsts.DefaultSubtreeRootThreshold)
+	require.Error(t, err)
+
+	builder, err := square.NewBuilder(2, appconsts.DefaultSubtreeRootThreshold)
+	require.NoError(t, err)
+	ns, err := share.NewBlobNamespaceV0([]byte("full"))
+	require.NoError(t, err)
+	bl, err := blob.NewBlobV0(ns, bytes.Repeat([]byte{1}, 2_000))
+	require.NoError(t, err)
+	require.False(t, builder.AppendBlobTx([]byte("pfb"), bl))
+	require.True(t, builder.AppendTx([]byte("tx")))
+	require.False(t, builder.AppendTx(bytes.Repeat([]byte{1}, 2_000)))
+
+	sq, err := builder.Export()
+	require.NoError(t, err)
+	require.Equal(t, 1, sq.Width)
+}
@@ -0,0 +1,75 @@
+package square
+
+import (
+	"fmt"
+
+	"github.com/celestiaorg/rsmt2d"
+
+	"github.com/celestiaorg/celestia-openrpc/types/core"
+	"github.com/celestiaorg/celestia-openrpc/types/share"
+)
+
+// Square is a data square built locally, along with the data availability
+// header of a block holding it.
+type Square struct {
+	// Width is the width of the original data square.
+	Width int
+	// Shares are the shares of the original data square in row-major order.
+	Shares []share.Share
+	// EDS is the extended data square.
+	EDS *rsmt2d.ExtendedDataSquare
+	// DAH holds the NMT roots of the rows and columns of EDS.
+	DAH *core.DataAvailabilityHeader
+	// DataHash is the hash of DAH, the data root of a block holding the
+	// square.
+	DataHash []byte
+
+	// blobs are the ranges of the blobs, by PFB and by blob of the PFB.
+	blobs [][]share.Range
+}
+
+func newSquare(width int, shares []share.Share, blobs [][]share.Range) (*Square, error) {
+	eds, err := rsmt2d.ComputeExtendedDataSquare(
+		share.ToBytes(shares),
+		share.DefaultRSMT2DCodec(),
+		NewConstructor(uint(width)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("square: extending square: %w", err)
+	}
+	dah, err := core.NewDataAvailabilityHeader(eds)
+	if err != nil {
+		return nil, fmt.Errorf("square: computing data availability header: %w", err)
+	}
+	return &Square{
+		Width:    width,
+		Shares:   shares,
+		EDS:      eds,
+		DAH:      &dah,
+		DataHash: dah.Hash(),
+		blobs:    blobs,
+	}, nil
+}
+
+// BlobRange returns the range of the shares of blob blobIndex of PFB
+// pfbIndex in the original data square, counting the PFBs in the order they
+// were appended to the Builder.
+func (s *Square) BlobRange(pfbIndex, blobIndex int) (share.Range, error) {
+	if pfbIndex < 0 || pfbIndex >= len(s.blobs) {
+		return share.Range{}, fmt.Errorf("square: PFB %d out of range", pfbIndex)
+	}
+	if blobIndex < 0 || blobIndex >= len(s.blobs[pfbIndex]) {
+		return share.Range{}, fmt.Errorf("square: blob %d of PFB %d out of range", blobIndex, pfbIndex)
+	}
+	return s.blobs[pfbIndex][blobIndex], nil
+}
+
+// BlobIndex returns the index of the first share of blob blobIndex of PFB
+// pfbIndex in the extended data square, as the node reports it.
+func (s *Square) BlobIndex(pfbIndex, blobIndex int) (int, error) {
+	r, err := s.BlobRange(pfbIndex, blobIndex)
+	if err != nil {
+		return 0, err
+	}
+	return r.Start/s.Width*2*s.Width + r.Start%s.Width, nil
+}
@@ -0,0 +1,80 @@
+package square
+
+import (
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/celestiaorg/nmt"
+	"github.com/celestiaorg/nmt/namespace"
+	"github.com/celestiaorg/rsmt2d"
+
+	"github.com/celestiaorg/celestia-openrpc/types/appconsts"
+	"github.com/celestiaorg/celestia-openrpc/types/blob"
+	"github.com/celestiaorg/celestia-openrpc/types/share"
+)
+
+// ErasuredNamespacedMerkleTree computes the NMT root of a row or column of an
+// extended data square as celestia-app does, namespacing the shares outside
+// of the original data square as parity shares.
+type ErasuredNamespacedMerkleTree struct {
+	squareSize uint
+	axisIndex  uint
+	shareIndex uint
+	tree       *nmt.NamespacedMerkleTree
+}
+
+// NewConstructor returns the constructor of the trees of an extended data
+// square whose original data square is squareSize wide, to be passed to
+// rsmt2d.
+func NewConstructor(squareSize uint) rsmt2d.TreeConstructorFn {
+	return func(_ rsmt2d.Axis, axisIndex uint) rsmt2d.Tree {
+		return NewErasuredNamespacedMerkleTree(squareSize, axisIndex)
+	}
+}
+
+// NewErasuredNamespacedMerkleTree returns the tree of the row or column
+// axisIndex of an extended data square whose original data square is
+// squareSize wide.
+func NewErasuredNamespacedMerkleTree(squareSize, axisIndex uint) *ErasuredNamespacedMerkleTree {
+	return &ErasuredNamespacedMerkleTree{
+		squareSize: squareSize,
+		axisIndex:  axisIndex,
+		tree: nmt.New(sha256.New(),
+			nmt.NamespaceIDSize(appconsts.NamespaceSize),
+			nmt.IgnoreMaxNamespace(blob.NMTIgnoreMaxNamespace),
+			nmt.InitialCapacity(int(2*squareSize)),
+		),
+	}
+}
+
+// Push adds the next share of the row or column to the tree.
+func (t *ErasuredNamespacedMerkleTree) Push(data []byte) error {
+	if len(data) < appconsts.NamespaceSize {
+		return fmt.Errorf("share of %d bytes is too short to contain a namespace", len(data))
+	}
+	ns := data[:appconsts.NamespaceSize]
+	if t.axisIndex >= t.squareSize || t.shareIndex >= t.squareSize {
+		ns = share.ParitySharesNamespace
+	}
+	t.shareIndex++
+
+	leaf := make([]byte, 0, len(ns)+len(data))
+	leaf = append(leaf, ns...)
+	return t.tree.Push(append(leaf, data...))
+}
+
+// Root returns the root of the tree.
+func (t *ErasuredNamespacedMerkleTree) Root() ([]byte, error) {
+	return t.tree.Root()
+}
+
+// ProveRange proves the shares [start, end) pushed to the tree.
+func (t *ErasuredNamespacedMerkleTree) ProveRange(start, end int) (nmt.Proof, error) {
+	return t.tree.ProveRange(start, end)
+}
+
+// ProveNamespace proves the shares of namespace ns pushed to the tree, or
+// their absence.
+func (t *ErasuredNamespacedMerkleTree) ProveNamespace(ns namespace.ID) (nmt.Proof, error) {
+	return t.tree.ProveNamespace(ns)
+}
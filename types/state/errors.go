@@ -0,0 +1,12 @@
+package state
+
+import "errors"
+
+var (
+	// ErrInsufficientFunds is returned when the account cannot pay for a
+	// transaction.
+	ErrInsufficientFunds = errors.New("state: insufficient funds")
+	// ErrTxTimeout is returned when a submitted transaction was not included
+	// in a block in time.
+	ErrTxTimeout = errors.New("state: timed out waiting for tx to be committed")
+)